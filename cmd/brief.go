@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -25,23 +25,35 @@ For quick mode, use 'impl' as the phase name:
 
 For full mode, specify phase:
   foreman brief 1-setup
-  foreman brief 2-backend`,
+  foreman brief 2-backend
+
+Output formats (--format):
+  markdown    Plain markdown (default), saved as <phase>.md
+  xml         Sections wrapped in XML-style tags, saved as <phase>.xml
+  json        Structured sections for programmatic use, saved as <phase>.json
+  agents-md   AGENTS.md-style instruction file, saved as <phase>.agents.md
+
+Add --repo-context to include a Repository Context section: a file tree that
+respects .gitignore, detected language and module files, the git branch and
+HEAD, and the diffstat since the previous phase was completed.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-
-		root, err := project.FindRoot(wd)
+		root, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
 
 		phaseName := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		if !brief.IsValidFormat(format) {
+			return fmt.Errorf("invalid format: %s (must be one of: %s)", format, strings.Join(brief.Formats, ", "))
+		}
+		repoContext, _ := cmd.Flags().GetBool("repo-context")
+		treeDepth, _ := cmd.Flags().GetInt("tree-depth")
+		opts := brief.Options{Format: format, RepoContext: repoContext, TreeDepth: treeDepth}
 
 		// Load state and sync phases
-		st, err := state.Load(root)
+		st, err := state.Load(dir)
 		if err != nil {
 			return err
 		}
@@ -53,12 +65,12 @@ For full mode, specify phase:
 				task = "Implementation task"
 			}
 			
-			briefContent, err := brief.GenerateQuickBriefAndSave(root, task)
+			briefContent, err := brief.GenerateQuickBriefWithOptionsAndSave(root, dir, task, opts)
 			if err != nil {
 				return err
 			}
 
-			briefPath := project.BriefPath(root, phaseName)
+			briefPath := brief.Path(dir, phaseName, format)
 			
 			green := color.New(color.FgGreen, color.Bold)
 			green.Printf("✓ ")
@@ -77,11 +89,11 @@ For full mode, specify phase:
 		}
 
 		// Full mode: sync phases
-		if err := project.SyncPhasesToState(root, st); err != nil {
+		if err := project.SyncPhasesToState(dir, st); err != nil {
 			return fmt.Errorf("failed to sync phases: %w", err)
 		}
 
-		if err := state.Save(root, st); err != nil {
+		if err := state.Save(dir, st); err != nil {
 			return err
 		}
 
@@ -95,12 +107,12 @@ For full mode, specify phase:
 		}
 
 		// Generate and save brief
-		briefContent, err := brief.GenerateAndSave(root, phaseName)
+		briefContent, err := brief.GenerateWithOptionsAndSave(root, dir, phaseName, opts)
 		if err != nil {
 			return err
 		}
 
-		briefPath := project.BriefPath(root, phaseName)
+		briefPath := brief.Path(dir, phaseName, format)
 		
 		green := color.New(color.FgGreen, color.Bold)
 		green.Printf("✓ ")
@@ -120,5 +132,8 @@ For full mode, specify phase:
 }
 
 func init() {
+	briefCmd.Flags().String("format", brief.FormatMarkdown, "Output format: markdown, xml, json, agents-md")
+	briefCmd.Flags().Bool("repo-context", false, "Include a Repository Context section (file tree, language, git info)")
+	briefCmd.Flags().Int("tree-depth", brief.DefaultTreeDepth, "Directory depth of the repository file tree")
 	rootCmd.AddCommand(briefCmd)
 }
\ No newline at end of file
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/thinkshake/foreman/internal/config"
 	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,21 +22,31 @@ var configCmd = &cobra.Command{
 var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
-	Long:  "Display the current project configuration from config.yaml.",
+	Long: `Display the effective project configuration.
+
+Settings are merged from these layers, each overriding the ones before:
+
+  1. defaults
+  2. global     ~/.config/foreman/config.yaml
+  3. project    .foreman/config.yaml
+  4. env        FOREMAN_* variables (e.g. FOREMAN_TESTING_FRAMEWORK)
+  5. flags      -c key=value
+
+Use --origin to see which layer set each value.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wd, err := os.Getwd()
+		_, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
 
-		root, err := project.FindRoot(wd)
+		cfg, origins, err := config.LoadWithOrigins(dir)
 		if err != nil {
 			return err
 		}
 
-		cfg, err := config.Load(root)
-		if err != nil {
-			return err
+		showOrigin, _ := cmd.Flags().GetBool("origin")
+		if showOrigin {
+			return printOrigins(cfg, origins)
 		}
 
 		// Pretty print the configuration
@@ -52,23 +63,114 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+// printOrigins lists each effective value with the layer that set it.
+func printOrigins(cfg *config.Config, origins map[string]config.Origin) error {
+	values, err := config.Values(cfg)
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, v := range values {
+		width = max(width, len(v.Path)+len(v.Value)+3)
+	}
+
+	dim := color.New(color.Faint)
+	for _, v := range values {
+		origin, ok := origins[v.Path]
+		if !ok {
+			origin = config.Origin{Layer: config.LayerDefault}
+		}
+		line := fmt.Sprintf("%s = %s", v.Path, v.Value)
+		fmt.Printf("%-*s  ", width, line)
+		dim.Println(origin)
+	}
+	return nil
+}
+
+const configKeysHelp = `Keys:
+  name, description             - Project name and description
+  tech_stack                    - Technology stack (comma-separated)
+  created                       - Creation time (RFC 3339)
+  reviewers.default             - Default reviewer (auto|human)
+  reviewers.overrides.<stage>   - Stage-specific reviewer override
+  preset                        - Workflow preset (read-only; use 'foreman upgrade --preset')
+  auto_advance                  - Auto-advance confidence threshold (0-100)
+  testing.style                 - Testing style (tdd|coverage|none)
+  testing.required              - Block phase completion without tests (true|false)
+  testing.framework             - Test framework hint (e.g. "go test")
+  testing.min_cover             - Minimum coverage percentage (0-100)
+  workflow                      - Workflow stages (comma-separated)
+  git.branches, git.tags,
+  git.commits                   - Git integration switches (true|false)
+  git.branch_prefix,
+  git.tag_prefix                - Git naming prefixes
+  default_track                 - Track used without --track (see 'foreman init --track')`
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the effective value of a configuration key, using dot notation.
+Sections such as 'testing' print all their settings. Unset keys print nothing.
+
+Examples:
+  foreman config get testing.framework
+  foreman config get workflow
+  foreman config get testing
+
+` + configKeysHelp,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		root, err := project.FindRoot(wd)
+		if err != nil {
+			return err
+		}
+
+		dir, err := settingDir(root, args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(dir)
+		if err != nil {
+			return err
+		}
+
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			fmt.Println(value)
+		}
+		return nil
+	},
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Long: `Set a configuration value using dot notation.
+	Long: `Set a configuration value using dot notation. Values are checked against
+the key's type and allowed values before config.yaml is written.
+
+Changing the workflow updates state.yaml: stages added before the current
+stage become the current stage, and the gates of later stages are blocked.
+Changing auto_advance updates the auto-advance threshold in state.yaml.
+The preset is switched with 'foreman upgrade --preset <name>' instead.
 
 Examples:
   foreman config set name "my-project"
-  foreman config set description "A sample project"
-  foreman config set reviewers.default human
-  foreman config set reviewers.overrides.requirements auto
+  foreman config set reviewers.overrides.requirements human
+  foreman config set testing.style coverage
+  foreman config set testing.min_cover 80
+  foreman config set workflow requirements,design,implementation
 
-Supported keys:
-  name                          - Project name
-  description                   - Project description  
-  tech_stack                    - Technology stack (comma-separated)
-  reviewers.default             - Default reviewer (auto|human)
-  reviewers.overrides.<stage>   - Stage-specific reviewer override`,
+` + configKeysHelp,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		wd, err := os.Getwd()
@@ -81,62 +183,117 @@ Supported keys:
 			return err
 		}
 
-		cfg, err := config.Load(root)
+		key := args[0]
+		value := args[1]
+
+		if err := checkPresetKey(key); err != nil {
+			return err
+		}
+		if key == "default_track" && value != config.MainTrack && !config.TrackExists(root, value) {
+			return fmt.Errorf("track %q does not exist (create it with 'foreman init --track %s')", value, value)
+		}
+		dir, err := settingDir(root, key)
 		if err != nil {
 			return err
 		}
 
-		key := args[0]
-		value := args[1]
-
-		// Handle different configuration keys
-		switch key {
-		case "name":
-			cfg.Name = value
-		case "description":
-			cfg.Description = value
-		case "tech_stack":
-			// Split comma-separated values
-			if value == "" {
-				cfg.TechStack = []string{}
-			} else {
-				cfg.TechStack = strings.Split(value, ",")
-				// Trim spaces
-				for i, tech := range cfg.TechStack {
-					cfg.TechStack[i] = strings.TrimSpace(tech)
-				}
-			}
-		case "reviewers.default":
-			if value != "auto" && value != "human" {
-				return fmt.Errorf("reviewers.default must be 'auto' or 'human', got: %s", value)
-			}
-			cfg.Reviewers.Default = value
-		default:
-			// Handle reviewers.overrides.<stage>
-			if strings.HasPrefix(key, "reviewers.overrides.") {
-				stage := strings.TrimPrefix(key, "reviewers.overrides.")
-				if value != "auto" && value != "human" {
-					return fmt.Errorf("reviewer must be 'auto' or 'human', got: %s", value)
-				}
-				cfg.Reviewers.SetReviewer(stage, value)
-			} else {
-				return fmt.Errorf("unknown configuration key: %s", key)
-			}
-		}
-
-		// Save the updated configuration
-		if err := config.Save(root, cfg); err != nil {
+		cfg, stateChanged, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+			return cfg.Set(key, value)
+		})
+		if err != nil {
 			return err
 		}
 
 		green := color.New(color.FgGreen)
 		green.Printf("✓ ")
 		fmt.Printf("Set %s = %s\n", key, formatValue(value))
+		reportConfigState(root, dir, cfg, stateChanged, "set "+key)
 
 		return nil
 	},
 }
 
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long: `Remove a configuration key from config.yaml so it takes its default value.
+Sections such as 'testing' are removed with all their settings.
+The preset can't be unset; switch it with 'foreman upgrade --preset <name>'.
+
+Examples:
+  foreman config unset testing.min_cover
+  foreman config unset reviewers.overrides.design
+  foreman config unset workflow
+
+` + configKeysHelp,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		root, err := project.FindRoot(wd)
+		if err != nil {
+			return err
+		}
+
+		key := args[0]
+		if err := checkPresetKey(key); err != nil {
+			return err
+		}
+		dir, err := settingDir(root, key)
+		if err != nil {
+			return err
+		}
+		cfg, stateChanged, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+			return cfg.Unset(key)
+		})
+		if err != nil {
+			return err
+		}
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Unset %s\n", key)
+		reportConfigState(root, dir, cfg, stateChanged, "unset "+key)
+
+		return nil
+	},
+}
+
+// checkPresetKey rejects changing the preset key on its own: the preset
+// defines the workflow, reviewers and auto_advance, which 'foreman upgrade'
+// switches together with state.yaml.
+func checkPresetKey(key string) error {
+	if key != "preset" {
+		return nil
+	}
+	return fmt.Errorf("the preset can't be changed with config set/unset; run 'foreman upgrade --preset <name>' to switch presets")
+}
+
+// settingDir returns the track directory whose config.yaml holds key: the
+// main track's for default_track, which is a setting of the whole project,
+// and the current track's for every other key.
+func settingDir(root, key string) (string, error) {
+	if key == "default_track" {
+		return config.TrackDir(root, config.MainTrack), nil
+	}
+	return trackDir(root)
+}
+
+// reportConfigState tells about state.yaml changes caused by a config change
+// and commits the track's state when git commits are enabled.
+func reportConfigState(root, dir string, cfg *config.Config, stateChanged bool, subject string) {
+	if stateChanged {
+		if st, err := state.Load(dir); err == nil {
+			dim := color.New(color.Faint)
+			dim.Printf("Updated state.yaml: workflow %s, current stage %s\n", strings.Join(st.GetActiveStages(), " → "), st.CurrentStage)
+		}
+	}
+	commitState(root, cfg, "config "+subject)
+}
+
 func formatValue(value string) string {
 	// Add quotes around string values for display
 	if strings.Contains(value, " ") || value == "" {
@@ -146,7 +303,10 @@ func formatValue(value string) string {
 }
 
 func init() {
+	configShowCmd.Flags().Bool("origin", false, "Show where each value comes from")
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
 	rootCmd.AddCommand(configCmd)
 }
\ No newline at end of file
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+)
+
+// TestCheckPresetKey tests that preset changes are sent to 'foreman upgrade'.
+func TestCheckPresetKey(t *testing.T) {
+	if err := checkPresetKey("preset"); err == nil || !strings.Contains(err.Error(), "foreman upgrade --preset") {
+		t.Errorf("expected the preset key to point to foreman upgrade, got %v", err)
+	}
+	if err := checkPresetKey("workflow"); err != nil {
+		t.Errorf("expected other keys to be accepted, got %v", err)
+	}
+}
+
+// TestSettingDir tests that default_track is kept in the main track and that
+// other keys need the current track to exist.
+func TestSettingDir(t *testing.T) {
+	root := t.TempDir()
+	currentTrack = "billing"
+	defer func() { currentTrack = config.MainTrack }()
+
+	if dir, err := settingDir(root, "default_track"); err != nil || dir != config.TrackDir(root, config.MainTrack) {
+		t.Errorf("expected default_track in the main track, got %s (%v)", dir, err)
+	}
+	if _, err := settingDir(root, "workflow"); err == nil || !strings.Contains(err.Error(), "foreman init --track billing") {
+		t.Errorf("expected the missing track to be reported, got %v", err)
+	}
+}
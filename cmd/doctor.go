@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/doctor"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check config, state and files for inconsistencies",
+	Long: `Checks the project for problems that no other command detects:
+
+  - config.yaml and state.yaml disagree about the workflow or auto-advance threshold
+  - state.yaml has gates for stages outside the workflow
+  - the current stage is outside the workflow, skipped a gate or is blocked
+  - invalid gate, phase or task statuses
+  - phases in state.yaml without a plan file, and plans missing from state.yaml
+  - briefs for phases that no longer exist
+
+With --fix, the safe repairs are applied: state.yaml follows the workflow of
+config.yaml, extra gates are removed, unstarted phases without a plan are
+dropped, new plans are added and stale briefs are deleted. Problems that
+would lose work are only reported.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		report, err := doctor.Check(dir)
+		if err != nil {
+			return err
+		}
+
+		green := color.New(color.FgGreen)
+		if len(report.Problems) == 0 {
+			green.Printf("✓ ")
+			fmt.Println("No problems found")
+			return nil
+		}
+
+		fix, _ := cmd.Flags().GetBool("fix")
+		if !fix {
+			printProblems(report.Problems)
+			fmt.Printf("%d problems found", len(report.Problems))
+			if n := report.Fixable(); n > 0 {
+				fmt.Printf(", %d can be fixed with 'foreman doctor --fix'", n)
+			}
+			fmt.Println()
+			return fmt.Errorf("doctor found %d problems", len(report.Problems))
+		}
+
+		fixed, err := report.Fix()
+		dim := color.New(color.Faint)
+		for _, p := range fixed {
+			green.Printf("✓ ")
+			fmt.Printf("Fixed: %s\n", p.Message)
+			dim.Printf("  %s\n", p.Repair)
+		}
+		if err != nil {
+			return err
+		}
+		if len(fixed) > 0 {
+			if cfg, err := config.Load(dir); err == nil {
+				commitState(root, cfg, fmt.Sprintf("doctor: fix %d problems", len(fixed)))
+			}
+		}
+
+		remaining := len(report.Problems) - len(fixed)
+		if remaining == 0 {
+			return nil
+		}
+		fmt.Println()
+		var manual []doctor.Problem
+		for _, p := range report.Problems {
+			if !p.Fixable() {
+				manual = append(manual, p)
+			}
+		}
+		printProblems(manual)
+		return fmt.Errorf("%d problems need a manual fix", remaining)
+	},
+}
+
+// printProblems lists problems with their explanation and repair.
+func printProblems(problems []doctor.Problem) {
+	red := color.New(color.FgRed)
+	dim := color.New(color.Faint)
+	for _, p := range problems {
+		red.Printf("✗ ")
+		fmt.Println(p.Message)
+		fmt.Printf("  %s\n", p.Explanation)
+		if p.Fixable() {
+			dim.Printf("  --fix: %s\n", p.Repair)
+		}
+	}
+	fmt.Println()
+}
+
+func init() {
+	doctorCmd.Flags().Bool("fix", false, "Apply the safe repairs")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -2,13 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/thinkshake/foreman/internal/config"
 	"github.com/thinkshake/foreman/internal/gate"
+	"github.com/thinkshake/foreman/internal/git"
 	"github.com/thinkshake/foreman/internal/project"
 	"github.com/thinkshake/foreman/internal/state"
 )
@@ -24,22 +24,17 @@ With stage name: checks that specific stage's gate.
 Gates control advancement between stages. Each gate validates that
 the stage work is complete before allowing progression.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wd, err := os.Getwd()
+		root, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
 
-		root, err := project.FindRoot(wd)
+		cfg, err := config.Load(dir)
 		if err != nil {
 			return err
 		}
 
-		cfg, err := config.Load(root)
-		if err != nil {
-			return err
-		}
-
-		st, err := state.Load(root)
+		st, err := state.Load(dir)
 		if err != nil {
 			return err
 		}
@@ -74,8 +69,11 @@ the stage work is complete before allowing progression.`,
 			if reviewer != "auto" && reviewer != "human" {
 				return fmt.Errorf("invalid reviewer: %s (must be 'auto' or 'human')", reviewer)
 			}
-			cfg.Reviewers.SetReviewer(targetStage, reviewer)
-			if err := config.Save(root, cfg); err != nil {
+			_, _, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+				cfg.Reviewers.SetReviewer(targetStage, reviewer)
+				return nil
+			})
+			if err != nil {
 				return err
 			}
 			fmt.Printf("✓ Set %s gate reviewer to: %s\n", targetStage, reviewer)
@@ -84,31 +82,31 @@ the stage work is complete before allowing progression.`,
 
 		// Handle approve
 		if approve {
-			return handleApprove(root, targetStage, st)
+			return handleApprove(root, dir, targetStage, cfg, st)
 		}
 
 		// Handle reject
 		if reject {
-			return handleReject(root, targetStage, reason, st)
+			return handleReject(root, dir, targetStage, reason, cfg, st)
 		}
 
 		// Default: validate gate
-		return handleValidate(root, targetStage, cfg, st)
+		return handleValidate(root, dir, targetStage, cfg, st)
 	},
 }
 
-func handleValidate(root, stage string, cfg *config.Config, st *state.State) error {
+func handleValidate(root, dir, stage string, cfg *config.Config, st *state.State) error {
 	// First sync phases if we're checking phases or implementation
 	if stage == "phases" || stage == "implementation" {
-		if err := project.SyncPhasesToState(root, st); err != nil {
+		if err := project.SyncPhasesToState(dir, st); err != nil {
 			return fmt.Errorf("failed to sync phases: %w", err)
 		}
-		if err := state.Save(root, st); err != nil {
+		if err := state.Save(dir, st); err != nil {
 			return err
 		}
 	}
 
-	result, err := gate.ValidateStage(root, stage, st)
+	result, err := gate.ValidateStage(root, dir, stage, st)
 	if err != nil {
 		return err
 	}
@@ -136,6 +134,13 @@ func handleValidate(root, stage string, cfg *config.Config, st *state.State) err
 		}
 		fmt.Println()
 	}
+	if len(result.Warnings) > 0 {
+		yellow := color.New(color.FgYellow)
+		for _, warning := range result.Warnings {
+			yellow.Printf("⚠️  %s\n", warning)
+		}
+		fmt.Println()
+	}
 
 	// If validation passes and gate is open, advance based on reviewer
 	if result.Passed && gate.Status == "open" {
@@ -144,9 +149,11 @@ func handleValidate(root, stage string, cfg *config.Config, st *state.State) err
 			if err := st.ApproveGate(stage, "auto"); err != nil {
 				return err
 			}
-			if err := state.Save(root, st); err != nil {
+			if err := state.Save(dir, st); err != nil {
 				return err
 			}
+			commitState(root, cfg, fmt.Sprintf("approve %s gate", stage), gateTrailers(stage, "approved", "auto")...)
+			tagGateApproval(root, cfg, stage, "auto")
 
 			cyan := color.New(color.FgCyan, color.Bold)
 			cyan.Printf("🎉 Gate approved automatically!\n")
@@ -164,9 +171,10 @@ func handleValidate(root, stage string, cfg *config.Config, st *state.State) err
 			if err := st.SetGateStatus(stage, "pending-review"); err != nil {
 				return err
 			}
-			if err := state.Save(root, st); err != nil {
+			if err := state.Save(dir, st); err != nil {
 				return err
 			}
+			commitState(root, cfg, fmt.Sprintf("%s gate ready for review", stage), gateTrailers(stage, "pending-review", "")...)
 
 			yellow := color.New(color.FgYellow, color.Bold)
 			yellow.Printf("⏳ Gate validation passed - awaiting human review\n")
@@ -179,7 +187,7 @@ func handleValidate(root, stage string, cfg *config.Config, st *state.State) err
 	return nil
 }
 
-func handleApprove(root, stage string, st *state.State) error {
+func handleApprove(root, dir, stage string, cfg *config.Config, st *state.State) error {
 	gate := st.Gates[stage]
 	if gate == nil {
 		return fmt.Errorf("stage %s not found", stage)
@@ -193,9 +201,11 @@ func handleApprove(root, stage string, st *state.State) error {
 		return err
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		return err
 	}
+	commitState(root, cfg, fmt.Sprintf("approve %s gate", stage), gateTrailers(stage, "approved", "human")...)
+	tagGateApproval(root, cfg, stage, "human")
 
 	green := color.New(color.FgGreen, color.Bold)
 	green.Printf("✓ Gate %s approved!\n", stage)
@@ -212,7 +222,7 @@ func handleApprove(root, stage string, st *state.State) error {
 	return nil
 }
 
-func handleReject(root, stage, reason string, st *state.State) error {
+func handleReject(root, dir, stage, reason string, cfg *config.Config, st *state.State) error {
 	gate := st.Gates[stage]
 	if gate == nil {
 		return fmt.Errorf("stage %s not found", stage)
@@ -226,9 +236,14 @@ func handleReject(root, stage, reason string, st *state.State) error {
 		return err
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		return err
 	}
+	trailers := gateTrailers(stage, "rejected", "")
+	if reason != "" {
+		trailers = append(trailers, git.Trailer{Key: "Foreman-Reason", Value: reason})
+	}
+	commitState(root, cfg, fmt.Sprintf("reject %s gate", stage), trailers...)
 
 	red := color.New(color.FgRed, color.Bold)
 	red.Printf("✗ Gate %s rejected\n", stage)
@@ -240,6 +255,18 @@ func handleReject(root, stage, reason string, st *state.State) error {
 	return nil
 }
 
+// gateTrailers returns the commit trailers describing a gate transition.
+func gateTrailers(stage, status, reviewer string) []git.Trailer {
+	trailers := []git.Trailer{
+		{Key: "Foreman-Gate", Value: stage},
+		{Key: "Foreman-Gate-Status", Value: status},
+	}
+	if reviewer != "" {
+		trailers = append(trailers, git.Trailer{Key: "Foreman-Reviewer", Value: reviewer})
+	}
+	return trailers
+}
+
 func init() {
 	gateCmd.Flags().Bool("approve", false, "Manually approve a pending gate")
 	gateCmd.Flags().Bool("reject", false, "Reject a pending gate")
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/fatih/color"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/gitsync"
+	"github.com/thinkshake/foreman/internal/hooks"
+	"github.com/thinkshake/foreman/internal/project"
+)
+
+// gitWarned avoids repeating the "not a git repository" warning within one command.
+var gitWarned bool
+
+// gitReady reports whether the opt-in git integration can run in root,
+// warning when it is configured but root is not inside a git repository.
+func gitReady(root string, cfg *config.Config) bool {
+	if cfg.Git == nil {
+		return false
+	}
+	if !git.IsRepo(root) {
+		if !gitWarned {
+			yellow := color.New(color.FgYellow)
+			yellow.Println("⚠️  git integration is configured but this is not a git repository; skipping")
+			gitWarned = true
+		}
+		return false
+	}
+	return true
+}
+
+// checkoutPhaseBranch switches to the phase's branch when git branches are enabled.
+func checkoutPhaseBranch(root string, cfg *config.Config, phaseName string) error {
+	if !cfg.GitBranchesEnabled() || !gitReady(root, cfg) {
+		return nil
+	}
+
+	branch := cfg.Git.PhaseBranch(currentTrack, phaseName)
+	if err := git.CheckoutBranch(root, branch); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", branch, err)
+	}
+
+	dim := color.New(color.Faint)
+	dim.Printf("On branch %s\n", branch)
+	return nil
+}
+
+// statePaths returns the paths, relative to the project root, that a state
+// commit of track includes. A named track commits its own directory and the
+// shared .foreman/config.yaml (which holds default_track); the main track
+// commits .foreman/ without the named tracks.
+func statePaths(track string) []string {
+	if track == config.MainTrack {
+		return []string{project.ForemanDir, ":(exclude)" + path.Join(project.ForemanDir, config.TracksDir)}
+	}
+	return []string{path.Join(project.ForemanDir, config.TracksDir, track), path.Join(project.ForemanDir, "config.yaml")}
+}
+
+// commitState commits the current track's files in .foreman/ with a structured
+// message when git commits are enabled, leaving other tracks' changes alone.
+// Failures are reported as warnings: the state change itself has already been saved.
+func commitState(root string, cfg *config.Config, subject string, trailers ...git.Trailer) {
+	if !cfg.GitCommitsEnabled() || !gitReady(root, cfg) {
+		return
+	}
+
+	paths := statePaths(currentTrack)
+	changed, err := git.HasChanges(root, paths...)
+	if err == nil && !changed {
+		return
+	}
+
+	if currentTrack != config.MainTrack {
+		trailers = append(trailers, git.Trailer{Key: hooks.TrackTrailer, Value: currentTrack})
+	}
+	// The change is already in state.yaml; 'sync --git' must not apply it again
+	trailers = append(trailers, git.Trailer{Key: gitsync.AppliedTrailer, Value: "true"})
+	message := git.FormatMessage("foreman: "+subject, trailers)
+	if err == nil {
+		err = git.CommitPaths(root, message, paths...)
+	}
+	if err != nil {
+		yellow := color.New(color.FgYellow)
+		yellow.Printf("⚠️  Failed to commit state: %v\n", err)
+		return
+	}
+
+	dim := color.New(color.Faint)
+	if head, err := git.Head(root); err == nil {
+		dim.Printf("Committed state as %s\n", git.ShortHash(head))
+	}
+}
+
+// tagGateApproval creates an annotated tag for an approved gate when git tags are enabled.
+// Gate tags are never moved: when a reopened gate is approved again, the new
+// approval gets the next free suffixed tag (foreman/design-approved-2, ...).
+func tagGateApproval(root string, cfg *config.Config, stage, approvedBy string) {
+	if !cfg.GitTagsEnabled() || !gitReady(root, cfg) {
+		return
+	}
+
+	base := cfg.Git.GateTag(currentTrack, stage)
+	tag := git.FreeTagName(root, base)
+	message := fmt.Sprintf("foreman: %s gate approved by %s", stage, approvedBy)
+	if err := git.TagAnnotated(root, tag, message); err != nil {
+		yellow := color.New(color.FgYellow)
+		yellow.Printf("⚠️  Failed to tag %s: %v\n", tag, err)
+		return
+	}
+
+	dim := color.New(color.Faint)
+	if tag != base {
+		dim.Printf("Tagged re-approval as %s (%s marks the earlier approval)\n", tag, base)
+		return
+	}
+	dim.Printf("Tagged %s\n", tag)
+}
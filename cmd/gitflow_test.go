@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+)
+
+// TestTagGateReapproval tests that approving a reopened gate again adds a
+// suffixed tag and leaves the earlier approval's tag in place.
+func TestTagGateReapproval(t *testing.T) {
+	dir, err := os.MkdirTemp("", "foreman-tag-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	for _, args := range [][]string{
+		{"config", "user.name", "foreman"},
+		{"config", "user.email", "foreman@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if _, err := git.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.NewDefault("tag-test")
+	cfg.Git = &config.Git{Tags: true}
+
+	tagGateApproval(dir, cfg, "design", "alice")
+	first, err := git.Run(dir, "rev-parse", "foreman/design-approved")
+	if err != nil {
+		t.Fatalf("expected the first approval to be tagged: %v", err)
+	}
+
+	if _, err := git.Run(dir, "commit", "-q", "--allow-empty", "-m", "reopened"); err != nil {
+		t.Fatal(err)
+	}
+	tagGateApproval(dir, cfg, "design", "bob")
+
+	if again, _ := git.Run(dir, "rev-parse", "foreman/design-approved"); again != first {
+		t.Error("expected the original approval tag not to move")
+	}
+	message, err := git.Run(dir, "tag", "-l", "--format=%(contents:subject)", "foreman/design-approved-2")
+	if err != nil || message != "foreman: design gate approved by bob" {
+		t.Errorf("expected the re-approval to be tagged foreman/design-approved-2, got %q (%v)", message, err)
+	}
+}
+
+// TestCommitStateTrack tests that a state commit includes only the current
+// track's files, so other tracks' changes are not attributed to it.
+func TestCommitStateTrack(t *testing.T) {
+	dir, err := os.MkdirTemp("", "foreman-commit-track-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(track string) { currentTrack = track }(currentTrack)
+
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	main := config.TrackDir(dir, config.MainTrack)
+	billing := config.TrackDir(dir, "billing")
+	if err := os.MkdirAll(billing, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(main, "config.yaml"), "name: demo\n")
+	write(filepath.Join(main, "state.yaml"), "current_stage: requirements\n")
+	write(filepath.Join(billing, "state.yaml"), "current_stage: requirements\n")
+	for _, args := range [][]string{
+		{"config", "user.name", "foreman"},
+		{"config", "user.email", "foreman@example.com"},
+		{"add", "."},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		if _, err := git.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.NewDefault("commit-track-test")
+	cfg.Git = &config.Git{Commits: true}
+
+	write(filepath.Join(main, "state.yaml"), "current_stage: design\n")
+	write(filepath.Join(billing, "state.yaml"), "current_stage: design\n")
+	write(filepath.Join(main, "config.yaml"), "name: demo\ndefault_track: billing\n")
+
+	currentTrack = "billing"
+	commitState(dir, cfg, "billing requirements approved")
+	files, err := git.Run(dir, "show", "--name-only", "--format=", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != ".foreman/config.yaml\n.foreman/tracks/billing/state.yaml" {
+		t.Errorf("expected the billing commit to include its track and the shared config, got:\n%s", files)
+	}
+	if changed, _ := git.HasChanges(dir, ".foreman/state.yaml"); !changed {
+		t.Error("expected the main track's change to stay uncommitted")
+	}
+
+	write(filepath.Join(billing, "state.yaml"), "current_stage: phases\n")
+	currentTrack = config.MainTrack
+	commitState(dir, cfg, "requirements approved")
+	if files, _ := git.Run(dir, "show", "--name-only", "--format=", "HEAD"); files != ".foreman/state.yaml" {
+		t.Errorf("expected the main commit to leave the billing track out, got:\n%s", files)
+	}
+	if changed, _ := git.HasChanges(dir, ".foreman/tracks/billing"); !changed {
+		t.Error("expected the billing track's change to stay uncommitted")
+	}
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/hooks"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Enforce the foreman workflow with git hooks",
+	Long: `Installs git hooks that enforce the foreman workflow on every commit:
+
+  pre-commit   Blocks code changes (files outside .foreman/) before the
+               implementation stage, rejects a staged .foreman/state.yaml
+               that is malformed or inconsistent, and rejects files outside
+               the in-progress phases' scope.
+  commit-msg   Requires code commits to carry a "Phase: <name>" trailer
+               naming an in-progress phase. Projects without phases are exempt.
+               On a named track, adds a "Foreman-Track: <name>" trailer to
+               commits naming a phase, for 'foreman sync --git'.
+
+Opting out:
+  FOREMAN_HOOKS=off git commit ...   Skip the checks for one commit
+  git commit --no-verify             Skip all git hooks for one commit
+  foreman hooks uninstall            Remove the hooks
+
+The hooks do nothing when foreman is not on PATH.`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the pre-commit and commit-msg hooks",
+	Long: `Writes the pre-commit and commit-msg hooks into the repository's hooks directory.
+
+Existing hooks that foreman did not write are left alone unless --force is given.
+
+Example:
+  foreman hooks install
+  foreman hooks install --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+
+		paths, err := hooks.Install(root, hooks.Names, force)
+		if err != nil {
+			return err
+		}
+
+		green := color.New(color.FgGreen)
+		for _, path := range paths {
+			green.Printf("✓ ")
+			fmt.Printf("Installed hook: %s\n", path)
+		}
+		dim := color.New(color.Faint)
+		dim.Printf("Skip for one commit with %s=off or 'git commit --no-verify'\n", hooks.DisableEnv)
+		return nil
+	},
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the hooks installed by foreman",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+
+		removed, err := hooks.Uninstall(root)
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Println("No foreman hooks installed")
+			return nil
+		}
+
+		green := color.New(color.FgGreen)
+		for _, path := range removed {
+			green.Printf("✓ ")
+			fmt.Printf("Removed hook: %s\n", path)
+		}
+		return nil
+	},
+}
+
+var hooksRunCmd = &cobra.Command{
+	Use:          "run <pre-commit|commit-msg> [message-file]",
+	Short:        "Run a hook's checks (called by the installed hooks)",
+	Hidden:       true,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if hooks.Disabled() {
+			return nil
+		}
+
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+
+		staged, err := git.StagedFiles(root)
+		if err != nil {
+			return err
+		}
+
+		var problems []string
+		switch args[0] {
+		case "pre-commit":
+			stateFile := hooks.StateFile(root, dir)
+			indexState, _ := git.StagedContent(root, stateFile)
+			problems = hooks.CheckPreCommit(root, dir, st, staged, indexState, git.FileAt(root, "HEAD", stateFile))
+		case "commit-msg":
+			if len(args) < 2 {
+				return fmt.Errorf("commit-msg needs the commit message file")
+			}
+			message, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read commit message: %w", err)
+			}
+			problems = hooks.CheckCommitMsg(st, string(message), staged)
+			if len(problems) == 0 {
+				if err := hooks.AddTrackTrailer(root, args[1], currentTrack); err != nil {
+					return fmt.Errorf("failed to add the %s trailer: %w", hooks.TrackTrailer, err)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown hook: %s (expected pre-commit or commit-msg)", args[0])
+		}
+
+		if len(problems) == 0 {
+			return nil
+		}
+
+		red := color.New(color.FgRed, color.Bold)
+		red.Printf("✗ foreman %s check failed:\n", args[0])
+		for _, p := range problems {
+			fmt.Printf("  • %s\n", p)
+		}
+		dim := color.New(color.Faint)
+		dim.Printf("Bypass with %s=off or 'git commit --no-verify'\n", hooks.DisableEnv)
+		return fmt.Errorf("commit rejected by foreman %s hook", args[0])
+	},
+}
+
+// findProjectRoot locates the foreman project containing the working directory.
+func findProjectRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return project.FindRoot(wd)
+}
+
+func init() {
+	hooksInstallCmd.Flags().Bool("force", false, "Replace existing hooks not written by foreman")
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/thinkshake/foreman/internal/config"
 	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/wizard"
 )
 
 var initCmd = &cobra.Command{
@@ -21,18 +22,47 @@ Presets (v2.1):
   --preset light     Small tool: requirements gate only, no design phase
   --preset full      Product: full workflow with design and phases
 
+Custom presets are loaded from ~/.config/foreman/presets/*.yaml and, when
+adding a track, from the project's .foreman/presets/*.yaml (see 'foreman
+preset list'). Unknown preset names are an error.
+
 Legacy aliases (v3 compat):
   --preset nightly   Alias for minimal
   --preset product   Alias for full
 
 TDD Integration:
-  --tdd              Enable test-driven development mode`,
+  --tdd              Enable test-driven development mode
+
+Blueprints:
+  --template go-cli          Command-line tool in Go
+  --template rest-service    HTTP/JSON API service with a database
+  --template library         Reusable library with a documented public API
+  --template ./my-blueprint  A blueprint directory (blueprint.yaml,
+                             requirements.md, designs/, phases/)
+
+Tracks:
+  --track <name>     Add a named track to an existing project, with its own
+                     requirements, gates and phases in .foreman/tracks/<name>/
+                     (names start with a letter, e.g. billing or api-v2)
+
+Interactive setup:
+  --interactive      Prompt for name, description, tech stack, preset,
+                     testing and gate reviewers. Answers are read one per
+                     line from stdin, so the wizard can be scripted:
+
+  printf 'my-app\n\nGo\nlight\ntdd\n\n\n\n\ny\n' | foreman init -i`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name, _ := cmd.Flags().GetString("name")
 		dir, _ := cmd.Flags().GetString("dir")
 		preset, _ := cmd.Flags().GetString("preset")
 		quick, _ := cmd.Flags().GetBool("quick")
 		tdd, _ := cmd.Flags().GetBool("tdd")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		template, _ := cmd.Flags().GetString("template")
+		track, _ := cmd.Flags().GetString("track")
+		if track == config.MainTrack {
+			track = "" // the main track is the project itself
+		}
 
 		if dir == "" {
 			wd, err := os.Getwd()
@@ -40,6 +70,13 @@ TDD Integration:
 				return err
 			}
 			dir = wd
+
+			// A track is added to the project the current directory belongs to
+			if track != "" {
+				if root, err := project.FindRoot(wd); err == nil {
+					dir = root
+				}
+			}
 		}
 
 		abs, err := filepath.Abs(dir)
@@ -53,9 +90,36 @@ TDD Integration:
 		}
 
 		opts := project.InitOptions{
-			Name:   name,
-			Preset: preset,
-			TDD:    tdd,
+			Name:     name,
+			Preset:   preset,
+			TDD:      tdd,
+			Template: template,
+			Track:    track,
+		}
+
+		if interactive {
+			if opts.Name == "" {
+				opts.Name = filepath.Base(abs)
+			}
+			w := wizard.New(cmd.InOrStdin(), cmd.OutOrStdout())
+			presetRoot := ""
+			if track != "" {
+				presetRoot = abs
+			}
+			opts, err = w.InitOptions(presetRoot, opts)
+			if err != nil {
+				return err
+			}
+			fmt.Println()
+			ok, err := w.Confirm(fmt.Sprintf("Create %s project %q in %s?", config.NormalizePreset(opts.Preset), opts.Name, abs), true)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("init cancelled")
+			}
+			fmt.Println()
+			preset, tdd = opts.Preset, opts.TDD
 		}
 
 		root, err := project.InitWithOptions(abs, opts)
@@ -63,6 +127,13 @@ TDD Integration:
 			return err
 		}
 
+		// A blueprint may have chosen the preset and testing style
+		cfg, err := config.LoadProject(config.TrackDir(root, track))
+		if err != nil {
+			return err
+		}
+		preset, tdd = cfg.Preset, cfg.IsTDDEnabled()
+
 		green := color.New(color.FgGreen, color.Bold)
 		green.Printf("✓ ")
 
@@ -76,23 +147,41 @@ TDD Integration:
 			mode = " (light mode)"
 		case config.PresetFull:
 			mode = " (full workflow)"
+		case "":
+		default:
+			mode = fmt.Sprintf(" (%s preset)", normalizedPreset)
 		}
 		if tdd {
 			mode += " + TDD"
 		}
-		fmt.Printf("Initialized foreman %s project%s in %s\n", version, mode, root)
+		if track == "" {
+			fmt.Printf("Initialized foreman %s project%s in %s\n", version, mode, root)
+		} else {
+			fmt.Printf("Created track %s%s in %s\n", track, mode, root)
+		}
 		fmt.Println()
 
+		foremanDir, err := filepath.Rel(root, config.TrackDir(root, track))
+		if err != nil {
+			return err
+		}
+		foremanDir = filepath.ToSlash(foremanDir)
+
 		dim := color.New(color.Faint)
 		dim.Println("Created:")
-		dim.Println("  .foreman/config.yaml")
-		dim.Println("  .foreman/state.yaml")
-		dim.Println("  .foreman/requirements.md")
-		if normalizedPreset == config.PresetFull {
-			dim.Println("  .foreman/designs/")
-			dim.Println("  .foreman/phases/")
-		}
-		dim.Println("  .foreman/briefs/")
+		dim.Printf("  %s/config.yaml\n", foremanDir)
+		dim.Printf("  %s/state.yaml\n", foremanDir)
+		dim.Printf("  %s/requirements.md\n", foremanDir)
+		if cfg.HasDesignPhase() {
+			dim.Printf("  %s/designs/\n", foremanDir)
+		}
+		if cfg.HasPhasesPhase() {
+			dim.Printf("  %s/phases/\n", foremanDir)
+		}
+		dim.Printf("  %s/briefs/\n", foremanDir)
+		if template != "" {
+			dim.Printf("  (documents from blueprint %s)\n", template)
+		}
 		fmt.Println()
 
 		cyan := color.New(color.FgCyan)
@@ -100,18 +189,21 @@ TDD Integration:
 		switch normalizedPreset {
 		case config.PresetMinimal:
 			fmt.Println("  foreman status              # Check current stage")
-			fmt.Println("  # Edit .foreman/requirements.md with your task")
+			fmt.Printf("  # Edit %s/requirements.md with your task\n", foremanDir)
 			fmt.Println("  foreman brief impl          # Generate brief and start building")
 		case config.PresetLight:
 			fmt.Println("  foreman status              # Check current stage")
-			fmt.Println("  # Edit .foreman/requirements.md with your task")
+			fmt.Printf("  # Edit %s/requirements.md with your task\n", foremanDir)
 			fmt.Println("  foreman gate requirements   # Advance to implementation")
 			fmt.Println("  foreman brief impl          # Generate brief for coding agent")
 		default:
 			fmt.Println("  foreman status              # Check current stage")
-			fmt.Println("  # Edit .foreman/requirements.md with your project requirements")
+			fmt.Printf("  # Edit %s/requirements.md with your project requirements\n", foremanDir)
 			fmt.Println("  foreman gate requirements   # Validate and advance past requirements")
 		}
+		if track != "" {
+			dim.Printf("\nRun commands with --track %s, or make it the default with\n'foreman config set default_track %s'.\n", track, track)
+		}
 
 		return nil
 	},
@@ -120,8 +212,10 @@ TDD Integration:
 func init() {
 	initCmd.Flags().String("name", "", "Project name (defaults to directory name)")
 	initCmd.Flags().String("dir", "", "Directory to initialize (defaults to cwd)")
-	initCmd.Flags().String("preset", "", "Workflow preset: minimal, light, full (or aliases: nightly, product)")
+	initCmd.Flags().String("preset", "", "Workflow preset: minimal, light, full, or a custom preset (see 'foreman preset list')")
 	initCmd.Flags().Bool("quick", false, "Shorthand for --preset minimal")
 	initCmd.Flags().Bool("tdd", false, "Enable test-driven development mode")
+	initCmd.Flags().String("template", "", "Start from a blueprint: go-cli, rest-service, library or a directory")
+	initCmd.Flags().BoolP("interactive", "i", false, "Prompt for the project settings (answers can be piped through stdin)")
 	rootCmd.AddCommand(initCmd)
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create design documents and phase plans from templates",
+	Long: `Scaffolds planning documents with the sections the gates look for, using
+the naming conventions foreman expects. Existing files are never overwritten.
+
+Templates follow the project's settings: phase plans get a Tests section when
+a testing style is configured, and workflows without a design or phases stage
+refuse to create those documents.`,
+}
+
+// newProjectContext locates the project and the current track and loads the
+// track's config for the new subcommands.
+func newProjectContext() (string, string, *config.Config, error) {
+	root, dir, err := findTrack()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return root, dir, cfg, nil
+}
+
+var newDesignCmd = &cobra.Command{
+	Use:   "design <name>",
+	Short: "Create a design document",
+	Long: `Creates designs/<name>.md. Names may include subdirectories.
+
+Example:
+  foreman new design architecture
+  foreman new design backend/api`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, dir, cfg, err := newProjectContext()
+		if err != nil {
+			return err
+		}
+
+		path, err := project.NewDesign(dir, args[0], cfg)
+		if err != nil {
+			return err
+		}
+
+		commitState(root, cfg, "new design "+project.NormalizeDesignName(args[0]))
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Created %s\n", path)
+		return nil
+	},
+}
+
+var newPhaseCmd = &cobra.Command{
+	Use:   "phase <title>",
+	Short: "Create the next phase plan and register it",
+	Long: `Creates phases/<N>-<slug>.md, numbered after the existing phases, and adds
+the phase to state.yaml. --parent nests the phase under an existing one
+(phases/<parent>/<N>-<slug>.md).
+
+Example:
+  foreman new phase "User authentication"      # → 3-user-authentication
+  foreman new phase Login --parent 3-user-authentication`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parent, _ := cmd.Flags().GetString("parent")
+
+		root, dir, cfg, err := newProjectContext()
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+		if parent != "" {
+			if err := project.SyncPhasesToState(dir, st); err != nil {
+				return fmt.Errorf("failed to sync phases: %w", err)
+			}
+			if st.GetPhase(parent) == nil {
+				return fmt.Errorf("parent phase %s not found", parent)
+			}
+		}
+
+		name, err := project.NewPhase(dir, parent, strings.Join(args, " "), cfg)
+		if err != nil {
+			return err
+		}
+
+		// Register the new phase
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+		if err := state.Save(dir, st); err != nil {
+			return err
+		}
+
+		commitState(root, cfg, "new phase "+name)
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Created phase %s: %s\n", name, project.PhasePlanPath(dir, name))
+		return nil
+	},
+}
+
+var newOverviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Create phases/overview.md",
+	Long:  "Creates phases/overview.md with a numbered list of the existing phases.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, dir, cfg, err := newProjectContext()
+		if err != nil {
+			return err
+		}
+
+		path, err := project.NewOverview(dir, cfg)
+		if err != nil {
+			return err
+		}
+
+		commitState(root, cfg, "new phase overview")
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Created %s\n", path)
+		return nil
+	},
+}
+
+func init() {
+	newPhaseCmd.Flags().String("parent", "", "Nest the phase under an existing phase")
+	newCmd.AddCommand(newDesignCmd)
+	newCmd.AddCommand(newPhaseCmd)
+	newCmd.AddCommand(newOverviewCmd)
+	rootCmd.AddCommand(newCmd)
+}
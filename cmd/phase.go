@@ -2,43 +2,84 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
 	"github.com/thinkshake/foreman/internal/project"
 	"github.com/thinkshake/foreman/internal/state"
 )
 
 var phaseCmd = &cobra.Command{
-	Use:   "phase <name> <status>",
+	Use:   "phase <name> [status]",
 	Short: "Update phase status",
 	Long: `Update the status of a phase during the implementation stage.
 
 Valid statuses: planned | in-progress | done
 
+When marking a phase done, --report stores a handoff report (what was built,
+deviations, follow-ups) in .foreman/reports/<name>.md. Briefs for later phases
+include the reports of the phases they depend on. Use '-' to read from stdin.
+
+Inside a git repository, the HEAD commit is recorded when a phase goes
+in-progress and again when it is marked done. --commits and --diff show the
+commits and the diff of that range, so one phase's work can be reviewed alone.
+
 Example:
   foreman phase 1-setup in-progress
-  foreman phase 2-backend done`,
-	Args: cobra.ExactArgs(2),
+  foreman phase 2-backend done
+  foreman phase 2-backend done --report handoff.md
+  cat handoff.md | foreman phase 2-backend done --report -
+  foreman phase 2-backend --commits
+  foreman phase 2-backend --diff`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wd, err := os.Getwd()
+		root, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
 
-		root, err := project.FindRoot(wd)
+		cfg, err := config.Load(dir)
 		if err != nil {
 			return err
 		}
 
-		st, err := state.Load(root)
+		st, err := state.Load(dir)
 		if err != nil {
 			return err
 		}
 
 		phaseName := args[0]
+		showCommits, _ := cmd.Flags().GetBool("commits")
+		showDiff, _ := cmd.Flags().GetBool("diff")
+		if showCommits || showDiff {
+			if len(args) > 1 {
+				return fmt.Errorf("--commits and --diff cannot be combined with a status change")
+			}
+			return showPhaseChanges(root, dir, st, phaseName, showCommits, showDiff)
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("missing status (planned | in-progress | done)")
+		}
+
 		phaseStatus := args[1]
+		reportSource, _ := cmd.Flags().GetString("report")
+
+		// Read the report up front so a bad source leaves state untouched
+		var report string
+		if reportSource != "" {
+			if phaseStatus != "done" {
+				return fmt.Errorf("--report can only be used when marking a phase done")
+			}
+			report, err = readReport(reportSource)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Validate we're in implementation stage
 		if st.CurrentStage != "implementation" {
@@ -49,22 +90,57 @@ Example:
 		}
 
 		// Sync phases from directory first
-		if err := project.SyncPhasesToState(root, st); err != nil {
+		if err := project.SyncPhasesToState(dir, st); err != nil {
 			return fmt.Errorf("failed to sync phases: %w", err)
 		}
 
 		// Update phase status
+		previousStatus := ""
+		if phase := st.GetPhase(phaseName); phase != nil {
+			previousStatus = phase.Status
+		}
 		if err := st.SetPhaseStatus(phaseName, phaseStatus); err != nil {
 			return err
 		}
 
-		if err := state.Save(root, st); err != nil {
+		// Work on a phase happens on its own branch (git.branches)
+		if phaseStatus == "in-progress" {
+			if err := checkoutPhaseBranch(root, cfg, phaseName); err != nil {
+				return err
+			}
+		}
+
+		// Record HEAD so the phase's commit range can be audited later
+		if phaseStatus != previousStatus && git.IsRepo(root) {
+			if head, err := git.Head(root); err == nil {
+				st.GetPhase(phaseName).RecordCommit(phaseStatus, head)
+			}
+		}
+
+		if err := state.Save(dir, st); err != nil {
 			return err
 		}
 
+		// The report is written once the state is saved, so a failed save
+		// leaves no report behind
+		if report != "" {
+			if err := project.WritePhaseReport(dir, phaseName, report); err != nil {
+				return err
+			}
+		}
+
+		commitState(root, cfg, fmt.Sprintf("phase %s → %s", phaseName, phaseStatus),
+			git.Trailer{Key: "Foreman-Phase", Value: phaseName},
+			git.Trailer{Key: "Foreman-Status", Value: phaseStatus},
+		)
+
 		green := color.New(color.FgGreen)
 		green.Printf("✓ ")
 		fmt.Printf("Updated phase %s to: %s\n", phaseName, phaseStatus)
+		if report != "" {
+			dim := color.New(color.Faint)
+			dim.Printf("Report saved to: %s\n", project.ReportPath(dir, phaseName))
+		}
 
 		// Show current phase status summary
 		fmt.Println()
@@ -90,6 +166,78 @@ Example:
 	},
 }
 
+// showPhaseChanges prints the commits and/or diff recorded for a phase.
+func showPhaseChanges(root, dir string, st *state.State, phaseName string, showCommits, showDiff bool) error {
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		return fmt.Errorf("failed to sync phases: %w", err)
+	}
+
+	phase := st.GetPhase(phaseName)
+	if phase == nil {
+		return fmt.Errorf("phase %s not found", phaseName)
+	}
+	if !git.IsRepo(root) {
+		return fmt.Errorf("not a git repository: %s", root)
+	}
+
+	revRange := phase.CommitRange()
+	if revRange == "" {
+		return fmt.Errorf("no commits recorded for phase %s (it has not been marked in-progress inside a git repository)", phaseName)
+	}
+
+	dim := color.New(color.Faint)
+	dim.Printf("Phase %s (%s): %s\n\n", phaseName, phase.Status, revRange)
+
+	if showCommits {
+		log, err := git.Log(root, revRange)
+		if err != nil {
+			return err
+		}
+		if log == "" {
+			fmt.Println("(no commits)")
+		} else {
+			fmt.Println(log)
+		}
+		if showDiff {
+			fmt.Println()
+		}
+	}
+
+	if showDiff {
+		diff, err := git.Diff(root, revRange)
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			fmt.Println("(no changes)")
+		} else {
+			fmt.Println(diff)
+		}
+	}
+
+	return nil
+}
+
+// readReport reads a handoff report from a file, or from stdin when source is "-".
+func readReport(source string) (string, error) {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read report: %w", err)
+	}
+
+	report := strings.TrimSpace(string(data))
+	if report == "" {
+		return "", fmt.Errorf("report is empty")
+	}
+	return report, nil
+}
+
 func getPhaseStatusIndicator(status string) string {
 	switch status {
 	case "done":
@@ -104,5 +252,8 @@ func getPhaseStatusIndicator(status string) string {
 }
 
 func init() {
+	phaseCmd.Flags().String("report", "", "Handoff report file to store when marking done ('-' for stdin)")
+	phaseCmd.Flags().Bool("commits", false, "Show the commits made during the phase")
+	phaseCmd.Flags().Bool("diff", false, "Show the diff of the phase's commit range")
 	rootCmd.AddCommand(phaseCmd)
 }
\ No newline at end of file
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var phasesCmd = &cobra.Command{
+	Use:   "phases",
+	Short: "Work with the phase plans as a whole",
+}
+
+var phasesGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Create missing phase plans from phases/overview.md",
+	Long: `Reads the phases listed in phases/overview.md and creates a plan stub for
+each one that has no plan file yet, filled in with its title and goal.
+
+The overview can list phases as a numbered list:
+
+  ## Phases
+
+  1. **Setup** — project skeleton and CI
+  2. **Backend** — REST API and storage
+     1. **Auth** — login and sessions
+
+or as a table:
+
+  | # | Title   | Goal                          |
+  |---|---------|-------------------------------|
+  | 1 | Setup   | Project skeleton and CI       |
+  | 2 | Backend | REST API and storage          |
+
+Phases are matched to plan files by number, so existing plans are never
+touched. Nested entries become dotted phases (2.1-auth).
+
+Example:
+  foreman phases generate --dry-run
+  foreman phases generate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		root, dir, cfg, err := newProjectContext()
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			diff, err := project.DiffOverview(root, dir)
+			if err != nil {
+				return err
+			}
+			if len(diff.Listed) == 0 {
+				return fmt.Errorf("no phases found in %s: list them as a numbered list or table", project.PhaseOverviewPath(dir))
+			}
+			if len(diff.Missing) == 0 {
+				fmt.Println("All phases in overview.md have plans")
+			}
+			for _, phase := range diff.Missing {
+				fmt.Printf("Would create %s\n", project.PhasePlanPath(dir, phase.Name))
+			}
+			return nil
+		}
+
+		created, err := project.GeneratePhases(root, dir, cfg)
+		if err != nil {
+			return err
+		}
+		if len(created) == 0 {
+			fmt.Println("All phases in overview.md have plans")
+			return nil
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+		if err := state.Save(dir, st); err != nil {
+			return err
+		}
+
+		commitState(root, cfg, fmt.Sprintf("generate %d phase plans from overview", len(created)))
+
+		green := color.New(color.FgGreen)
+		for _, name := range created {
+			green.Printf("✓ ")
+			fmt.Printf("Created phase %s: %s\n", name, project.PhasePlanPath(dir, name))
+		}
+		return nil
+	},
+}
+
+func init() {
+	phasesGenerateCmd.Flags().Bool("dry-run", false, "List the plans that would be created")
+	phasesCmd.AddCommand(phasesGenerateCmd)
+	rootCmd.AddCommand(phasesCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"gopkg.in/yaml.v3"
+)
+
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "List and inspect workflow presets",
+	Long: `Presets bundle the workflow, reviewers, auto-advance threshold, testing
+style and gate rules that 'foreman init --preset' applies.
+
+Besides the built-in presets (minimal, light, full), presets can be defined
+in YAML files:
+
+  ~/.config/foreman/presets/*.yaml   your presets, for every project
+  .foreman/presets/*.yaml            presets of the current project
+
+Example preset (~/.config/foreman/presets/team.yaml):
+
+  description: Our default for services
+  workflow: [requirements, design, implementation]
+  reviewers:
+    default: auto
+    overrides:
+      design: human
+  auto_advance: 50
+  testing:
+    style: tdd
+    framework: go test
+  gates:
+    skip: false   # true approves every gate before implementation at init`,
+}
+
+// presetRoot returns the current project root, or "" outside a project.
+func presetRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	root, err := project.FindRoot(wd)
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+var presetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available presets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		presets, err := config.LoadPresets(presetRoot())
+		if err != nil {
+			return err
+		}
+
+		dim := color.New(color.Faint)
+		for _, p := range presets {
+			fmt.Printf("%-12s %s\n", p.Name, strings.Join(p.Workflow, " → "))
+			if p.Description != "" {
+				fmt.Printf("%-12s %s\n", "", p.Description)
+			}
+			if p.Source != config.PresetSourceBuiltin {
+				dim.Printf("%-12s %s\n", "", p.Source)
+			}
+		}
+		return nil
+	},
+}
+
+var presetShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the settings of a preset",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := config.FindPreset(presetRoot(), args[0])
+		if err != nil {
+			return err
+		}
+
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return err
+		}
+
+		dim := color.New(color.Faint)
+		dim.Printf("# %s (%s)\n", p.Name, p.Source)
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	presetCmd.AddCommand(presetListCmd)
+	presetCmd.AddCommand(presetShowCmd)
+	rootCmd.AddCommand(presetCmd)
+}
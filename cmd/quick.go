@@ -53,6 +53,10 @@ This creates a minimal .foreman/ with:
 			name = filepath.Base(abs)
 		}
 
+		if currentTrack != config.MainTrack {
+			return fmt.Errorf("quick creates a new project; add a track with 'foreman init --track %s --preset minimal'", currentTrack)
+		}
+
 		// Check if .foreman already exists
 		foremanDir := filepath.Join(abs, ".foreman")
 		if _, err := os.Stat(foremanDir); err == nil {
@@ -87,13 +91,13 @@ This creates a minimal .foreman/ with:
 			Preset:      "nightly",
 			AutoAdvance: autoAdvance,
 		}
-		if err := config.Save(abs, cfg); err != nil {
+		if err := config.Save(foremanDir, cfg); err != nil {
 			return fmt.Errorf("failed to create config.yaml: %w", err)
 		}
 
 		// Create state with task
 		st := state.NewQuickMode(task, autoAdvance)
-		if err := state.Save(abs, st); err != nil {
+		if err := state.Save(foremanDir, st); err != nil {
 			return fmt.Errorf("failed to create state.yaml: %w", err)
 		}
 
@@ -137,11 +141,11 @@ _Specify preferred technologies._
 			st.Gates["requirements"].Status = "approved"
 			st.CurrentStage = "implementation"
 			st.Gates["implementation"] = &state.Gate{Status: "open"}
-			if err := state.Save(abs, st); err != nil {
+			if err := state.Save(foremanDir, st); err != nil {
 				return fmt.Errorf("failed to update state: %w", err)
 			}
 
-			briefContent, err := brief.GenerateQuickBrief(abs, task)
+			briefContent, err := brief.GenerateQuickBrief(abs, foremanDir, task)
 			if err != nil {
 				return fmt.Errorf("failed to generate brief: %w", err)
 			}
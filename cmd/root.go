@@ -5,8 +5,14 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
 )
 
+// currentTrack is the track the command works on: the --track flag, else the
+// project's default_track setting. It is resolved once, before the command runs.
+var currentTrack = config.MainTrack
+
 var rootCmd = &cobra.Command{
 	Use:   "foreman",
 	Short: "🏗️  foreman v3 — AI-native project management CLI",
@@ -23,6 +29,55 @@ QUICK MODE (v3):
 
 Phases are self-contained implementation units that get handed off to coding agents
 via compiled briefs containing all the context needed for independent execution.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		overrides, _ := cmd.Flags().GetStringArray("config")
+		if err := config.SetOverrides(overrides); err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("track")
+		var root string
+		if wd, err := os.Getwd(); err == nil {
+			root, _ = project.FindRoot(wd) // outside a project only --track applies
+		}
+		track, err := config.ResolveTrack(root, name)
+		if err != nil {
+			return err
+		}
+		currentTrack = track
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayP("config", "c", nil, "Override a config value for this command (key=value, repeatable)")
+	rootCmd.PersistentFlags().String("track", "", "Work on a named track in .foreman/tracks/<name>/ (default: the default_track setting, or main)")
+}
+
+// findTrack locates the project containing the working directory and returns
+// its root and the directory of the current track.
+func findTrack() (string, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	root, err := project.FindRoot(wd)
+	if err != nil {
+		return "", "", err
+	}
+	dir, err := trackDir(root)
+	if err != nil {
+		return "", "", err
+	}
+	return root, dir, nil
+}
+
+// trackDir returns the directory of the current track in the project at root.
+// It fails when the track doesn't exist.
+func trackDir(root string) (string, error) {
+	if currentTrack != config.MainTrack && !config.TrackExists(root, currentTrack) {
+		return "", fmt.Errorf("track %q does not exist in %s\nCreate it with 'foreman init --track %s', or use --track main", currentTrack, root, currentTrack)
+	}
+	return config.TrackDir(root, currentTrack), nil
 }
 
 // Execute runs the root command.
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/hooks"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/scope"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var scopeCmd = &cobra.Command{
+	Use:   "scope",
+	Short: "Guard phases against out-of-scope changes",
+	Long: `Phase plans can declare which files they are allowed to change:
+
+  ---
+  scope: ["internal/api/**", "cmd/server/**"]
+  ---
+  # Phase 2: API
+
+'foreman scope check' lists changed files that fall outside the active
+phase's scope. Files under .foreman/ are always allowed, and phases without
+a scope are not restricted.`,
+}
+
+var scopeCheckCmd = &cobra.Command{
+	Use:   "check [phase]",
+	Short: "List changed files outside the phase scope",
+	Long: `Compare changed files against a phase's scope.
+
+Without a phase argument, the scopes of all in-progress phases are used.
+
+By default the working tree (including untracked files) is checked.
+  --staged        Check only staged files (used by the pre-commit hook)
+  --range A..B    Check the files changed in a commit range
+  --phase-range   Check the phase's recorded commit range
+
+Exits with an error when any file is out of scope.
+
+Example:
+  foreman scope check
+  foreman scope check 2-api --staged
+  foreman scope check 2-api --phase-range`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		staged, _ := cmd.Flags().GetBool("staged")
+		revRange, _ := cmd.Flags().GetString("range")
+		phaseRange, _ := cmd.Flags().GetBool("phase-range")
+
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		if !git.IsRepo(root) {
+			return fmt.Errorf("not a git repository: %s", root)
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+
+		// Determine which phases' scopes apply
+		var phases []string
+		if len(args) > 0 {
+			if st.GetPhase(args[0]) == nil {
+				return fmt.Errorf("phase %s not found", args[0])
+			}
+			phases = []string{args[0]}
+		} else {
+			for _, phase := range st.Phases {
+				if phase.Status == "in-progress" {
+					phases = append(phases, phase.Name)
+				}
+			}
+		}
+		if len(phases) == 0 {
+			dim := color.New(color.Faint)
+			dim.Println("No phase in progress; nothing to check")
+			return nil
+		}
+
+		var patterns []string
+		for _, name := range phases {
+			meta, err := project.ReadPhaseMeta(dir, name)
+			if err != nil {
+				return err
+			}
+			if len(meta.Scope) == 0 {
+				dim := color.New(color.Faint)
+				dim.Printf("Phase %s declares no scope; all files are allowed\n", name)
+				return nil
+			}
+			patterns = append(patterns, meta.Scope...)
+		}
+
+		// Collect the files to check
+		var files []string
+		source := "working tree"
+		switch {
+		case phaseRange:
+			if len(phases) != 1 {
+				return fmt.Errorf("--phase-range needs a single phase")
+			}
+			revRange = st.GetPhase(phases[0]).CommitRange()
+			if revRange == "" {
+				return fmt.Errorf("no commits recorded for phase %s", phases[0])
+			}
+			fallthrough
+		case revRange != "":
+			files, err = git.RangeFiles(root, revRange)
+			source = revRange
+		case staged:
+			files, err = git.StagedFiles(root)
+			source = "staged changes"
+		default:
+			files, err = git.ChangedFiles(root)
+		}
+		if err != nil {
+			return err
+		}
+
+		outside := scope.Outside(patterns, files)
+		if len(outside) == 0 {
+			green := color.New(color.FgGreen)
+			green.Printf("✓ ")
+			fmt.Printf("%d changed files within scope of %s (%s)\n", len(files), strings.Join(phases, ", "), source)
+			return nil
+		}
+
+		red := color.New(color.FgRed, color.Bold)
+		red.Printf("✗ %d files outside the scope of %s (%s):\n", len(outside), strings.Join(phases, ", "), source)
+		for _, f := range outside {
+			fmt.Printf("  %s\n", f)
+		}
+		fmt.Println()
+		fmt.Println("Allowed scope:")
+		for _, p := range patterns {
+			fmt.Printf("  %s\n", p)
+		}
+
+		return fmt.Errorf("%d files outside phase scope", len(outside))
+	},
+}
+
+var scopeInstallHookCmd = &cobra.Command{
+	Use:   "install-hook",
+	Short: "Install the foreman pre-commit hook (includes the scope check)",
+	Long: `Writes the foreman pre-commit hook, which rejects commits touching files
+outside the in-progress phase's scope along with the other pre-commit checks
+described in 'foreman hooks --help'. Use 'foreman hooks install' to also
+install the commit-msg hook.
+
+An existing pre-commit hook that foreman did not write is left alone unless
+--force is given. To opt out, run 'foreman hooks uninstall' or commit with
+'git commit --no-verify'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		root, err := project.FindRoot(wd)
+		if err != nil {
+			return err
+		}
+
+		paths, err := hooks.Install(root, []string{"pre-commit"}, force)
+		if err != nil {
+			return err
+		}
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Installed pre-commit hook: %s\n", paths[0])
+		return nil
+	},
+}
+
+func init() {
+	scopeCheckCmd.Flags().Bool("staged", false, "Check only staged files")
+	scopeCheckCmd.Flags().String("range", "", "Check files changed in a commit range (e.g. main..HEAD)")
+	scopeCheckCmd.Flags().Bool("phase-range", false, "Check the phase's recorded commit range")
+	scopeInstallHookCmd.Flags().Bool("force", false, "Replace an existing pre-commit hook")
+	scopeCmd.AddCommand(scopeCheckCmd)
+	scopeCmd.AddCommand(scopeInstallHookCmd)
+	rootCmd.AddCommand(scopeCmd)
+}
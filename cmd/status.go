@@ -2,42 +2,42 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/thinkshake/foreman/internal/config"
-	"github.com/thinkshake/foreman/internal/project"
 	"github.com/thinkshake/foreman/internal/state"
 )
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show project stage and gate status",
-	Long:  "Displays the current project stage, gate statuses, and phase progress.",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		wd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
+	Long: `Displays the current project stage, gate statuses, and phase progress.
 
-		root, err := project.FindRoot(wd)
+The details are those of the current track. When the project has named
+tracks, a summary of every track follows.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
 
-		cfg, err := config.Load(root)
+		cfg, err := config.Load(dir)
 		if err != nil {
 			return err
 		}
 
-		st, err := state.Load(root)
+		st, err := state.Load(dir)
 		if err != nil {
 			return err
 		}
 
 		// Project header
 		fmt.Printf("Project: %s\n", cfg.Name)
+		if currentTrack != config.MainTrack {
+			fmt.Printf("Track: %s\n", currentTrack)
+		}
 		if cfg.Description != "" {
 			dim := color.New(color.Faint)
 			dim.Printf("%s\n", cfg.Description)
@@ -58,7 +58,7 @@ var statusCmd = &cobra.Command{
 		
 		// Current stage
 		stages := st.GetActiveStages()
-		stageIndex := state.GetStageIndexForMode(st.CurrentStage, st.QuickMode) + 1
+		stageIndex := st.GetStageIndexInWorkflow(st.CurrentStage) + 1
 		totalStages := len(stages)
 		fmt.Printf("Stage: %s (%d/%d)\n\n", st.CurrentStage, stageIndex, totalStages)
 
@@ -90,7 +90,18 @@ var statusCmd = &cobra.Command{
 			fmt.Println("\nPhases:")
 			for _, phase := range st.Phases {
 				indicator := getPhaseIndicator(phase.Status)
-				fmt.Printf("  %s %-15s %s\n", indicator, phase.Name, phase.Status)
+				fmt.Printf("  %s %-15s %s%s\n", indicator, phase.Name, phase.Status, taskProgress(&phase))
+				if done, total := phase.TaskProgress(); phase.Status == "done" && done < total {
+					yellow := color.New(color.FgYellow)
+					yellow.Printf("      ⚠️  done, but %d of its tasks are not; run 'foreman doctor'\n", total-done)
+				}
+
+				// Tasks of phases being worked on
+				if phase.Status == "in-progress" {
+					for _, task := range phase.Tasks {
+						fmt.Printf("      %s %s\n", getPhaseIndicator(task.Status), task.ID)
+					}
+				}
 			}
 		} else if st.CurrentStage == "implementation" || state.GetStageIndex(st.CurrentStage) > state.GetStageIndex("phases") {
 			fmt.Println("\nPhases: (not yet defined)")
@@ -98,10 +109,67 @@ var statusCmd = &cobra.Command{
 			dim.Println("  Run sync to load phases from phases/ directory")
 		}
 
+		tracks, err := config.ListTracks(root)
+		if err != nil {
+			return err
+		}
+		if len(tracks) > 0 {
+			printTracks(root, currentTrack, append([]string{config.MainTrack}, tracks...))
+		}
+
 		return nil
 	},
 }
 
+// printTracks summarizes the stage, pending reviews and phase progress of
+// every track, marking the current one.
+func printTracks(root, current string, tracks []string) {
+	fmt.Println("\nTracks:")
+	for _, track := range tracks {
+		marker := " "
+		if track == current {
+			marker = "▸"
+		}
+
+		st, err := state.Load(config.TrackDir(root, track))
+		if err != nil {
+			fmt.Printf("  %s %-20s ", marker, track)
+			color.New(color.FgRed).Printf("%v\n", err)
+			continue
+		}
+
+		summary := fmt.Sprintf("%s (%d/%d)", st.CurrentStage, st.GetStageIndexInWorkflow(st.CurrentStage)+1, len(st.GetActiveStages()))
+		var pending []string
+		for _, stage := range st.GetActiveStages() {
+			if gate := st.Gates[stage]; gate != nil && gate.Status == "pending-review" {
+				pending = append(pending, stage)
+			}
+		}
+		if len(pending) > 0 {
+			summary += fmt.Sprintf(", %s awaiting review", strings.Join(pending, ", "))
+		}
+		if len(st.Phases) > 0 {
+			done := 0
+			for _, phase := range st.Phases {
+				if phase.Status == "done" {
+					done++
+				}
+			}
+			summary += fmt.Sprintf(", %d/%d phases done", done, len(st.Phases))
+		}
+		fmt.Printf("  %s %-20s %s\n", marker, track, summary)
+	}
+}
+
+// taskProgress returns " (done/total tasks)" for phases with tasks, or "".
+func taskProgress(phase *state.Phase) string {
+	done, total := phase.TaskProgress()
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d/%d tasks)", done, total)
+}
+
 func getGateIndicator(status string) string {
 	switch status {
 	case "approved":
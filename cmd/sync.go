@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/gitsync"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync state with the phases directory and git history",
+	Long: `Adds phases found in .foreman/phases/ to state.yaml.
+
+With --git, also scans the commits made since the last sync for trailers
+and applies the phase transitions they describe:
+
+  Add login endpoint
+
+  Foreman-Phase: 2-backend
+  Foreman-Status: done
+
+A phase trailer without a status ("Foreman-Phase" or "Phase") starts a
+planned phase. Transitions are validated like 'foreman phase'; invalid ones
+are reported and skipped. Only the commits of the current track are
+applied: on a named track, commits carry a "Foreman-Track: <name>" trailer,
+which the commit-msg hook adds; commits without one belong to the main track.
+The last scanned commit is stored in the track's state.yaml, so running sync
+again only looks at new commits. The first sync starts at the commit that
+added the track's state.yaml; if it was never committed, the whole history
+of HEAD is scanned.
+
+Example:
+  foreman sync
+  foreman sync --git`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromGit, _ := cmd.Flags().GetBool("git")
+
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(dir)
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+
+		before := len(st.Phases)
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("%d phases (%d new)\n", len(st.Phases), len(st.Phases)-before)
+
+		var result *gitsync.Result
+		if fromGit {
+			result, err = gitsync.Sync(root, currentTrack, st)
+			if err != nil {
+				return err
+			}
+			printSyncResult(st, result)
+		}
+
+		if err := state.Save(dir, st); err != nil {
+			return err
+		}
+
+		if result != nil && len(result.Applied) > 0 {
+			commitState(root, cfg, fmt.Sprintf("sync %d phase transitions from git", len(result.Applied)))
+		}
+		return nil
+	},
+}
+
+// printSyncResult logs the transitions applied and skipped by a git sync.
+func printSyncResult(st *state.State, result *gitsync.Result) {
+	dim := color.New(color.Faint)
+	yellow := color.New(color.FgYellow)
+
+	if result.Reset {
+		yellow.Println("⚠️  The last synced commit is not in HEAD's history; rescanning all commits")
+	}
+	dim.Printf("Scanned %d commits (%s)\n", result.Scanned, result.Range)
+
+	if len(result.Applied) > 0 && st.CurrentStage != "implementation" {
+		yellow.Printf("⚠️  Warning: Not in implementation stage (currently: %s)\n", st.CurrentStage)
+	}
+
+	for _, t := range result.Applied {
+		status := t.Status
+		if status == "" {
+			status = "in-progress"
+		}
+		fmt.Printf("  %s %s %-15s → %s\n", getPhaseStatusIndicator(status), git.ShortHash(t.Commit.Hash), t.Phase, status)
+	}
+	for _, s := range result.Skipped {
+		yellow.Printf("  ⚠️  %s %s: %s (skipped)\n", git.ShortHash(s.Transition.Commit.Hash), s.Transition.Phase, s.Reason)
+	}
+	if len(result.Applied) == 0 && len(result.Skipped) == 0 {
+		fmt.Println("No phase transitions found")
+	}
+
+	if st.AllPhasesDone() {
+		fmt.Println()
+		cyan := color.New(color.FgCyan, color.Bold)
+		cyan.Println("🎉 All phases completed!")
+		fmt.Println("Run 'foreman gate implementation' to complete the project")
+	}
+}
+
+func init() {
+	syncCmd.Flags().Bool("git", false, "Apply phase transitions from commit trailers since the last sync")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task <phase>[/<task>] [status]",
+	Short: "List or update the tasks of a phase",
+	Long: `Tasks are parsed from a phase plan, either from a "## Tasks" section:
+
+  ## Tasks
+  - Add login endpoint
+  - Add session middleware
+
+or from frontmatter:
+
+  ---
+  tasks: ["Add login endpoint", "Add session middleware"]
+  ---
+
+Each task gets an ID derived from its title (add-login-endpoint); tasks can
+also be referenced by position (1, 2, ...).
+
+Valid statuses: planned | in-progress | done
+
+Starting a task starts its phase, and the phase is marked done when its last
+task is done. A phase with unfinished tasks cannot be marked done directly.
+
+Example:
+  foreman task 2-backend
+  foreman task 2-backend/add-login-endpoint in-progress
+  foreman task 2-backend/2 done`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(dir)
+		if err != nil {
+			return err
+		}
+
+		st, err := state.Load(dir)
+		if err != nil {
+			return err
+		}
+
+		// Sync phases and tasks from the plans first
+		if err := project.SyncPhasesToState(dir, st); err != nil {
+			return fmt.Errorf("failed to sync phases: %w", err)
+		}
+
+		// A bare phase name lists its tasks
+		if phase := st.GetPhase(args[0]); phase != nil {
+			if len(args) > 1 {
+				return fmt.Errorf("missing task: use %s/<task> %s", args[0], args[1])
+			}
+			printTasks(phase, "")
+			return nil
+		}
+
+		phaseName, taskRef, err := splitTaskRef(args[0])
+		if err != nil {
+			return err
+		}
+		phase := st.GetPhase(phaseName)
+		if phase == nil {
+			return fmt.Errorf("phase %s not found", phaseName)
+		}
+		if len(args) < 2 {
+			task := phase.GetTask(taskRef)
+			if task == nil {
+				return fmt.Errorf("task %s not found in phase %s", taskRef, phaseName)
+			}
+			fmt.Printf("%s %s/%s: %s (%s)\n", getPhaseStatusIndicator(task.Status), phaseName, task.ID, task.Title, task.Status)
+			return nil
+		}
+
+		taskStatus := args[1]
+		previousStatus := phase.Status
+		task, err := st.SetTaskStatus(phaseName, taskRef, taskStatus)
+		if err != nil {
+			return err
+		}
+
+		// The phase may have rolled up; handle its transition like 'foreman phase'
+		phase = st.GetPhase(phaseName)
+		if phase.Status != previousStatus {
+			if phase.Status == "in-progress" {
+				if err := checkoutPhaseBranch(root, cfg, phaseName); err != nil {
+					return err
+				}
+			}
+			if git.IsRepo(root) {
+				if head, err := git.Head(root); err == nil {
+					phase.RecordCommit(phase.Status, head)
+				}
+			}
+		}
+
+		if err := state.Save(dir, st); err != nil {
+			return err
+		}
+
+		commitState(root, cfg, fmt.Sprintf("task %s/%s → %s", phaseName, task.ID, taskStatus),
+			git.Trailer{Key: "Foreman-Phase", Value: phaseName},
+			git.Trailer{Key: "Foreman-Task", Value: task.ID},
+			git.Trailer{Key: "Foreman-Task-Status", Value: taskStatus},
+		)
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Updated task %s/%s to: %s\n", phaseName, task.ID, taskStatus)
+		if phase.Status != previousStatus {
+			cyan := color.New(color.FgCyan)
+			cyan.Printf("Phase %s: %s → %s\n", phaseName, previousStatus, phase.Status)
+		}
+
+		fmt.Println()
+		printTasks(phase, task.ID)
+
+		if st.AllPhasesDone() {
+			fmt.Println()
+			cyan := color.New(color.FgCyan, color.Bold)
+			cyan.Println("🎉 All phases completed!")
+			fmt.Println("Run 'foreman gate implementation' to complete the project")
+		}
+
+		return nil
+	},
+}
+
+// splitTaskRef splits "<phase>/<task>" at the last slash, since nested phase names contain slashes.
+func splitTaskRef(ref string) (string, string, error) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("invalid task reference %q: expected <phase>/<task>", ref)
+	}
+	return ref[:i], ref[i+1:], nil
+}
+
+// printTasks lists a phase's tasks, marking the highlighted task.
+func printTasks(phase *state.Phase, highlightID string) {
+	done, total := phase.TaskProgress()
+	if total == 0 {
+		dim := color.New(color.Faint)
+		dim.Printf("Phase %s has no tasks (add a \"## Tasks\" list to its plan)\n", phase.Name)
+		return
+	}
+
+	fmt.Printf("Tasks for %s (%d/%d done):\n", phase.Name, done, total)
+	for i, task := range phase.Tasks {
+		highlight := ""
+		if task.ID == highlightID {
+			highlight = " ← updated"
+		}
+		fmt.Printf("  %s %d. %-30s %s%s\n", getPhaseStatusIndicator(task.Status), i+1, task.ID, task.Status, highlight)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+}
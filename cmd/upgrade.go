@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/wizard"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade --preset <name>",
+	Short: "Switch the project to another preset",
+	Long: `Switches a project to another preset mid-project, e.g. a light project
+that turned out to need design and phases. Downgrades work the same way.
+
+The workflow, reviewers and auto-advance threshold come from the new preset.
+Reviewer overrides set in the project and the testing settings are kept.
+Approved gates stay approved; the first stage without an approved gate becomes
+the current stage. designs/ and phases/ are created when the new workflow has
+those stages, and existing phases are carried over.
+
+A preview of the changes is shown first. config.yaml and state.yaml are only
+written after confirmation, and together.
+
+Examples:
+  foreman upgrade --preset full
+  foreman upgrade --preset light --yes
+  foreman upgrade --preset full --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preset, _ := cmd.Flags().GetString("preset")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		root, dir, err := findTrack()
+		if err != nil {
+			return err
+		}
+
+		u, err := project.PlanUpgrade(root, dir, preset)
+		if err != nil {
+			return err
+		}
+
+		printUpgrade(root, u)
+		if dryRun {
+			return nil
+		}
+
+		if !yes {
+			w := wizard.New(cmd.InOrStdin(), cmd.OutOrStdout())
+			ok, err := w.Confirm("Apply these changes?", false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("upgrade cancelled")
+			}
+		}
+
+		if err := u.Apply(); err != nil {
+			return err
+		}
+		commitState(root, u.Config, fmt.Sprintf("upgrade from %s to %s preset", u.From, u.To))
+
+		green := color.New(color.FgGreen)
+		green.Printf("✓ ")
+		fmt.Printf("Switched to the %s preset, current stage: %s\n", u.To, u.State.CurrentStage)
+		return nil
+	},
+}
+
+// printUpgrade shows what an upgrade changes.
+func printUpgrade(root string, u *project.Upgrade) {
+	bold := color.New(color.Bold)
+	dim := color.New(color.Faint)
+
+	bold.Printf("Upgrade %s: %s → %s preset\n\n", u.Config.Name, u.From, u.To)
+
+	fmt.Printf("Workflow:      %s\n", strings.Join(u.OldWorkflow, " → "))
+	fmt.Printf("          now  %s\n", strings.Join(u.State.Workflow, " → "))
+	if u.OldStage != u.State.CurrentStage {
+		fmt.Printf("Current stage: %s → %s\n", u.OldStage, u.State.CurrentStage)
+	} else {
+		fmt.Printf("Current stage: %s\n", u.State.CurrentStage)
+	}
+
+	if len(u.Gates) > 0 {
+		fmt.Println("Gates:")
+		for _, g := range u.Gates {
+			switch {
+			case g.From == "":
+				fmt.Printf("  + %-15s %s\n", g.Stage, g.To)
+			case g.To == "":
+				fmt.Printf("  - %-15s %s (removed)\n", g.Stage, g.From)
+			default:
+				fmt.Printf("  ~ %-15s %s → %s\n", g.Stage, g.From, g.To)
+			}
+		}
+	}
+
+	var reviewers []string
+	for _, stage := range u.State.Workflow {
+		reviewers = append(reviewers, fmt.Sprintf("%s=%s", stage, u.Config.Reviewers.GetReviewer(stage)))
+	}
+	fmt.Printf("Reviewers:     %s\n", strings.Join(reviewers, ", "))
+	fmt.Printf("Auto-advance:  %d\n", u.Config.AutoAdvance)
+
+	for _, dir := range u.Dirs {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			rel = dir
+		}
+		fmt.Printf("Create:        %s/\n", filepath.ToSlash(rel))
+	}
+	if n := len(u.State.Phases); n > 0 {
+		fmt.Printf("Phases:        %d carried over\n", n)
+	}
+	dim.Println("\nconfig.yaml and state.yaml are updated together.")
+	fmt.Println()
+}
+
+func init() {
+	upgradeCmd.Flags().String("preset", "", "Preset to switch to (see 'foreman preset list')")
+	upgradeCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+	upgradeCmd.Flags().Bool("dry-run", false, "Only show the preview")
+	upgradeCmd.MarkFlagRequired("preset")
+	rootCmd.AddCommand(upgradeCmd)
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/fatih/color"
@@ -24,12 +23,7 @@ Press Ctrl+C to stop watching.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		interval, _ := cmd.Flags().GetInt("interval")
 
-		wd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-
-		root, err := project.FindRoot(wd)
+		_, dir, err := findTrack()
 		if err != nil {
 			return err
 		}
@@ -45,32 +39,32 @@ Press Ctrl+C to stop watching.`,
 		defer ticker.Stop()
 
 		// Initial display
-		displayProgress(root, &lastStage, &lastPhaseStates, true)
+		displayProgress(dir, &lastStage, &lastPhaseStates, true)
 
 		for range ticker.C {
-			displayProgress(root, &lastStage, &lastPhaseStates, false)
+			displayProgress(dir, &lastStage, &lastPhaseStates, false)
 		}
 
 		return nil
 	},
 }
 
-func displayProgress(root string, lastStage *string, lastPhaseStates *map[string]string, initial bool) {
-	st, err := state.Load(root)
+func displayProgress(dir string, lastStage *string, lastPhaseStates *map[string]string, initial bool) {
+	st, err := state.Load(dir)
 	if err != nil {
 		fmt.Printf("Error loading state: %v\n", err)
 		return
 	}
 
-	cfg, err := config.Load(root)
+	cfg, err := config.Load(dir)
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
 		return
 	}
 
 	// Sync phases
-	_ = project.SyncPhasesToState(root, st)
-	_ = state.Save(root, st)
+	_ = project.SyncPhasesToState(dir, st)
+	_ = state.Save(dir, st)
 
 	// Check for stage change
 	stageChanged := *lastStage != st.CurrentStage
@@ -92,6 +86,16 @@ func displayProgress(root string, lastStage *string, lastPhaseStates *map[string
 			cyan.Printf("   📝 Phase %s: %s → %s\n", phase.Name, oldStatus, phase.Status)
 		}
 		(*lastPhaseStates)[phase.Name] = phase.Status
+
+		for _, task := range phase.Tasks {
+			key := "task:" + phase.Name + "/" + task.ID
+			oldStatus, exists := (*lastPhaseStates)[key]
+			if exists && oldStatus != task.Status && !initial {
+				dim := color.New(color.Faint)
+				dim.Printf("      Task %s/%s: %s → %s\n", phase.Name, task.ID, oldStatus, task.Status)
+			}
+			(*lastPhaseStates)[key] = task.Status
+		}
 	}
 
 	// Only show full status on initial or stage change
@@ -147,7 +151,7 @@ func displayProgress(root string, lastStage *string, lastPhaseStates *map[string
 				case "in-progress":
 					indicator = "🔵"
 				}
-				fmt.Printf("  %s %s\n", indicator, phase.Name)
+				fmt.Printf("  %s %s%s\n", indicator, phase.Name, taskProgress(&phase))
 			}
 		}
 	}
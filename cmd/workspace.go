@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/thinkshake/foreman/internal/workspace"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Work with several projects at once",
+	Long: `A workspace is a directory, typically a monorepo, holding several foreman
+projects. Its projects are listed in a foreman-workspace.yaml found in the
+directory or above it:
+
+  projects:
+    - web
+    - services/*   # every directory with a .foreman/ under services/
+
+Without that file, every directory with a .foreman/ under the current
+directory is a project (hidden directories, node_modules and vendor are
+skipped).`,
+}
+
+var workspaceStatusCmd = &cobra.Command{
+	Use:   "status [dir]",
+	Short: "Show stage, pending reviews and phase progress of every project",
+	Long: `Shows one line per project of the workspace: its current stage, the gates
+waiting for review and how many phases are done. Given a directory, only the
+projects under it are shown.
+
+Examples:
+  foreman workspace status
+  foreman workspace status --pending
+  foreman workspace status services --json`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pending, _ := cmd.Flags().GetBool("pending")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		ws, err := workspace.Open(dir)
+		if err != nil {
+			return err
+		}
+		// A workspace file above dir lists more projects than were asked for
+		if len(args) > 0 {
+			if ws, err = ws.Within(dir); err != nil {
+				return err
+			}
+		}
+
+		var statuses []workspace.ProjectStatus
+		for _, s := range ws.Status() {
+			if !pending || s.Pending() {
+				statuses = append(statuses, s)
+			}
+		}
+
+		if asJSON {
+			out := struct {
+				Root     string                    `json:"root"`
+				File     string                    `json:"file,omitempty"`
+				Projects []workspace.ProjectStatus `json:"projects"`
+				Missing  []string                  `json:"missing,omitempty"`
+			}{ws.Root, ws.File, statuses, ws.Missing}
+			if out.Projects == nil {
+				out.Projects = []workspace.ProjectStatus{}
+			}
+			data, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(ws.Projects) == 0 && len(ws.Missing) == 0 {
+			return fmt.Errorf("no projects found under %s\nRun 'foreman init' in each project, or list them in %s", ws.Root, workspace.FileName)
+		}
+		printWorkspace(statuses)
+
+		yellow := color.New(color.FgYellow)
+		for _, entry := range ws.Missing {
+			yellow.Printf("⚠️  ")
+			fmt.Printf("%s is listed in %s but has no .foreman/ directory\n", entry, workspace.FileName)
+		}
+
+		reviews := 0
+		for _, s := range statuses {
+			reviews += len(s.PendingReviews)
+		}
+		dim := color.New(color.Faint)
+		switch {
+		case pending && len(statuses) == 0:
+			fmt.Println("No pending reviews")
+		case pending:
+			dim.Printf("%d of %d projects, %d pending reviews\n", len(statuses), len(ws.Projects), reviews)
+		default:
+			dim.Printf("%d projects, %d pending reviews\n", len(statuses), reviews)
+		}
+		return nil
+	},
+}
+
+// printWorkspace prints the workspace table.
+func printWorkspace(statuses []workspace.ProjectStatus) {
+	if len(statuses) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tPATH\tSTAGE\tPENDING REVIEW\tPHASES")
+	for _, s := range statuses {
+		if s.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\terror: %s\t\t\n", s.Name, s.Path, strings.SplitN(s.Error, "\n", 2)[0])
+			continue
+		}
+		review := "-"
+		if s.Pending() {
+			review = strings.Join(s.PendingReviews, ", ")
+		}
+		phases := "-"
+		if s.Phases > 0 {
+			phases = fmt.Sprintf("%d/%d done", s.PhasesDone, s.Phases)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s (%d/%d)\t%s\t%s\n", s.Name, s.Path, s.Stage, s.StageNumber, s.Stages, review, phases)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+func init() {
+	workspaceStatusCmd.Flags().Bool("pending", false, "Only show projects with gates waiting for review")
+	workspaceStatusCmd.Flags().Bool("json", false, "Print the status as JSON")
+	workspaceCmd.AddCommand(workspaceStatusCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
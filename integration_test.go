@@ -1,11 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/thinkshake/foreman/internal/blueprint"
 	"github.com/thinkshake/foreman/internal/brief"
 	"github.com/thinkshake/foreman/internal/config"
 	"github.com/thinkshake/foreman/internal/gate"
@@ -13,6 +16,24 @@ import (
 	"github.com/thinkshake/foreman/internal/state"
 )
 
+// TestMain keeps the user's global config and FOREMAN_* variables out of the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "foreman-user-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, config.EnvPrefix) {
+			os.Unsetenv(key)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // TestFullWorkflow tests the complete project workflow from init to completion.
 func TestFullWorkflow(t *testing.T) {
 	// Create temporary directory
@@ -27,6 +48,7 @@ func TestFullWorkflow(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to initialize project: %v", err)
 	}
+	dir := project.ForemanPath(root)
 
 	// Verify project structure was created
 	expectedDirs := []string{
@@ -55,7 +77,7 @@ func TestFullWorkflow(t *testing.T) {
 	}
 
 	// Step 2: Load and verify initial state
-	st, err := state.Load(root)
+	st, err := state.Load(dir)
 	if err != nil {
 		t.Fatalf("failed to load state: %v", err)
 	}
@@ -70,7 +92,7 @@ func TestFullWorkflow(t *testing.T) {
 
 	// Step 3: Test requirements stage
 	// Initially should fail validation (placeholder content)
-	result, err := gate.ValidateStage(root, "requirements", st)
+	result, err := gate.ValidateStage(root, dir, "requirements", st)
 	if err != nil {
 		t.Fatalf("failed to validate requirements: %v", err)
 	}
@@ -109,7 +131,7 @@ Build a comprehensive CLI tool for managing AI-native software projects.
 	}
 
 	// Now validation should pass
-	result, err = gate.ValidateStage(root, "requirements", st)
+	result, err = gate.ValidateStage(root, dir, "requirements", st)
 	if err != nil {
 		t.Fatalf("failed to validate requirements: %v", err)
 	}
@@ -119,7 +141,7 @@ Build a comprehensive CLI tool for managing AI-native software projects.
 	}
 
 	// Approve requirements gate (auto reviewer)
-	cfg, err := config.Load(root)
+	cfg, err := config.Load(dir)
 	if err != nil {
 		t.Fatalf("failed to load config: %v", err)
 	}
@@ -133,7 +155,7 @@ Build a comprehensive CLI tool for managing AI-native software projects.
 		t.Fatalf("failed to approve requirements gate: %v", err)
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		t.Fatalf("failed to save state: %v", err)
 	}
 
@@ -205,7 +227,7 @@ Each stage has a gate that validates completion:
 	}
 
 	// Validate and approve design stage
-	result, err = gate.ValidateStage(root, "design", st)
+	result, err = gate.ValidateStage(root, dir, "design", st)
 	if err != nil {
 		t.Fatalf("failed to validate design: %v", err)
 	}
@@ -218,7 +240,7 @@ Each stage has a gate that validates completion:
 		t.Fatalf("failed to approve design gate: %v", err)
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		t.Fatalf("failed to save state: %v", err)
 	}
 
@@ -347,7 +369,7 @@ Each phase gets a comprehensive brief with:
 	}
 
 	// Validate and approve phases stage
-	result, err = gate.ValidateStage(root, "phases", st)
+	result, err = gate.ValidateStage(root, dir, "phases", st)
 	if err != nil {
 		t.Fatalf("failed to validate phases: %v", err)
 	}
@@ -362,11 +384,11 @@ Each phase gets a comprehensive brief with:
 
 	// Step 6: Test implementation stage
 	// First sync phases to state
-	if err := project.SyncPhasesToState(root, st); err != nil {
+	if err := project.SyncPhasesToState(dir, st); err != nil {
 		t.Fatalf("failed to sync phases: %v", err)
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		t.Fatalf("failed to save state: %v", err)
 	}
 
@@ -386,7 +408,7 @@ Each phase gets a comprehensive brief with:
 	}
 
 	// Test implementation validation (should fail - phases not done)
-	result, err = gate.ValidateStage(root, "implementation", st)
+	result, err = gate.ValidateStage(root, dir, "implementation", st)
 	if err != nil {
 		t.Fatalf("failed to validate implementation: %v", err)
 	}
@@ -403,7 +425,7 @@ Each phase gets a comprehensive brief with:
 	}
 
 	// Now implementation should validate
-	result, err = gate.ValidateStage(root, "implementation", st)
+	result, err = gate.ValidateStage(root, dir, "implementation", st)
 	if err != nil {
 		t.Fatalf("failed to validate implementation: %v", err)
 	}
@@ -417,7 +439,7 @@ Each phase gets a comprehensive brief with:
 		t.Fatalf("failed to approve implementation gate: %v", err)
 	}
 
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		t.Fatalf("failed to save state: %v", err)
 	}
 
@@ -450,46 +472,47 @@ func TestBriefGeneration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to initialize project: %v", err)
 	}
+	dir := project.ForemanPath(root)
 
 	// Set up basic content
 	reqContent := "# Requirements\n\nBuild a test project for brief generation."
-	if err := os.WriteFile(project.RequirementsPath(root), []byte(reqContent), 0644); err != nil {
+	if err := os.WriteFile(project.RequirementsPath(dir), []byte(reqContent), 0644); err != nil {
 		t.Fatal(err)
 	}
 
 	designContent := "# Design\n\nSimple test design."
-	designPath := filepath.Join(project.DesignsPath(root), "test.md")
+	designPath := filepath.Join(project.DesignsPath(dir), "test.md")
 	if err := os.WriteFile(designPath, []byte(designContent), 0644); err != nil {
 		t.Fatal(err)
 	}
 
 	overviewContent := "# Phase Overview\n\nTest phases for brief generation."
-	if err := os.WriteFile(project.PhaseOverviewPath(root), []byte(overviewContent), 0644); err != nil {
+	if err := os.WriteFile(project.PhaseOverviewPath(dir), []byte(overviewContent), 0644); err != nil {
 		t.Fatal(err)
 	}
 
 	phaseContent := "# Phase 1\n\nFirst test phase."
-	if err := os.WriteFile(project.PhasePlanPath(root, "1-test"), []byte(phaseContent), 0644); err != nil {
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-test"), []byte(phaseContent), 0644); err != nil {
 		t.Fatal(err)
 	}
 
 	// Set up state with phases
-	st, err := state.Load(root)
+	st, err := state.Load(dir)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := project.SyncPhasesToState(root, st); err != nil {
+	if err := project.SyncPhasesToState(dir, st); err != nil {
 		t.Fatal(err)
 	}
 
 	st.AddPhase("1-test")
-	if err := state.Save(root, st); err != nil {
+	if err := state.Save(dir, st); err != nil {
 		t.Fatal(err)
 	}
 
 	// Generate brief
-	briefContent, err := brief.GenerateAndSave(root, "1-test")
+	briefContent, err := brief.GenerateAndSave(root, dir, "1-test")
 	if err != nil {
 		t.Fatalf("failed to generate brief: %v", err)
 	}
@@ -513,8 +536,1305 @@ func TestBriefGeneration(t *testing.T) {
 	}
 
 	// Verify brief file was saved
-	briefPath := project.BriefPath(root, "1-test")
+	briefPath := project.BriefPath(dir, "1-test")
 	if _, err := os.Stat(briefPath); os.IsNotExist(err) {
 		t.Error("expected brief file to be saved")
 	}
-}
\ No newline at end of file
+}
+
+// TestBriefDesignSelection tests that phase frontmatter limits the designs in a brief.
+func TestBriefDesignSelection(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-designs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-designs-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	designs := map[string]string{
+		"api.md":         "---\n---\n# API\n\nEndpoint definitions.",
+		"data-model.md":  "---\ntags: [storage]\n---\n# Data Model\n\nTables and relations.",
+		"frontend.md":    "# Frontend\n\nComponent layout.",
+		"conventions.md": "---\nalways: true\n---\n# Conventions\n\nNaming rules.",
+	}
+	for name, content := range designs {
+		if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	phaseContent := "---\ndesigns: [api.md]\ntags: [storage]\n---\n# Phase 1\n\nBuild the backend."
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-backend"), []byte(phaseContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	briefContent, err := brief.Generate(root, dir, "1-backend")
+	if err != nil {
+		t.Fatalf("failed to generate brief: %v", err)
+	}
+
+	for _, included := range []string{"## api.md", "## data-model.md", "## conventions.md"} {
+		if !strings.Contains(briefContent, included) {
+			t.Errorf("expected brief to include %s", included)
+		}
+	}
+	if strings.Contains(briefContent, "## frontend.md") {
+		t.Error("expected brief to omit frontend.md")
+	}
+	if !strings.Contains(briefContent, "Omitted design documents (not selected for this phase): frontend.md") {
+		t.Error("expected brief to name the omitted design documents")
+	}
+	if strings.Contains(briefContent, "designs: [api.md]") {
+		t.Error("expected phase frontmatter to be stripped from the brief")
+	}
+	if strings.Contains(briefContent, "---\n# API") {
+		t.Error("expected empty design frontmatter to be stripped from the brief")
+	}
+
+	// Broken design frontmatter fails the brief instead of dropping its selection keys
+	if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), "conventions.md"), []byte("---\nalways: [\n---\n# Conventions"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := brief.Generate(root, dir, "1-backend"); err == nil || !strings.Contains(err.Error(), "designs/conventions.md") {
+		t.Errorf("expected broken design frontmatter to be reported, got %v", err)
+	}
+	if result := gate.ValidateDesign(root, dir); result.Passed {
+		t.Error("expected the design gate to fail on broken design frontmatter")
+	}
+
+	// So does a malformed designs/index.yaml
+	if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), "conventions.md"), []byte(designs["conventions.md"]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), project.DesignIndexFile), []byte("order: [api.md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := brief.Generate(root, dir, "1-backend"); err == nil || !strings.Contains(err.Error(), project.DesignIndexFile) {
+		t.Errorf("expected a malformed design index to be reported, got %v", err)
+	}
+}
+
+// TestBriefFormats tests that every output format renders from the same brief model.
+func TestBriefFormats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-formats-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-formats-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	reqContent := "# Requirements\n\nBuild a test project for brief formats."
+	if err := os.WriteFile(project.RequirementsPath(dir), []byte(reqContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	phaseContent := "# Phase 1\n\nFirst test phase."
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-test"), []byte(phaseContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		brief.FormatMarkdown: {"# Phase Brief: 1-test", "## Requirements\n\n# Requirements", "### Completion"},
+		brief.FormatXML:      {`<brief kind="phase" phase="1-test"`, `<requirements title="Requirements">`, "</dependencies>"},
+		brief.FormatJSON:     {`"kind": "phase"`, `"id": "requirements"`},
+		brief.FormatAgentsMD: {"# AGENTS.md", "phase **1-test**", "## Phase Spec: 1-test"},
+	}
+
+	for format, fragments := range expected {
+		content, err := brief.GenerateFormatAndSave(root, dir, "1-test", format)
+		if err != nil {
+			t.Fatalf("failed to generate %s brief: %v", format, err)
+		}
+		for _, fragment := range fragments {
+			if !strings.Contains(content, fragment) {
+				t.Errorf("expected %s brief to contain %q", format, fragment)
+			}
+		}
+		if _, err := os.Stat(brief.Path(dir, "1-test", format)); os.IsNotExist(err) {
+			t.Errorf("expected %s brief to be saved", format)
+		}
+	}
+
+	if _, err := brief.GenerateFormatAndSave(root, dir, "1-test", "yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	// Nested phase reports and markup in the documents keep the output well-formed
+	if err := os.MkdirAll(filepath.Join(project.PhasesPath(dir), "1-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-test/1-db"), []byte("# Phase 1.1: DB"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project.PhasePlanPath(dir, "2-api"), []byte("# Phase 2: API\n\nKeep `a < b && c` and `m[k[0]]>0` working."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.WritePhaseReport(dir, "1-test/1-db", "Tables <users> & <orders> are in place."); err != nil {
+		t.Fatal(err)
+	}
+	xmlBrief, err := brief.GenerateFormatAndSave(root, dir, "2-api", brief.FormatXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Phase string `xml:"phase,attr"`
+	}
+	if err := xml.Unmarshal([]byte(xmlBrief), &doc); err != nil || doc.Phase != "2-api" {
+		t.Errorf("expected well-formed xml for phase 2-api, got %v:\n%s", err, xmlBrief)
+	}
+	if !strings.Contains(xmlBrief, `<report-1-test-1-db id="report-1-test/1-db"`) {
+		t.Errorf("expected the nested phase report to keep its ID in an attribute:\n%s", xmlBrief)
+	}
+	jsonBrief, err := brief.GenerateFormatAndSave(root, dir, "2-api", brief.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid([]byte(jsonBrief)) {
+		t.Errorf("expected valid json:\n%s", jsonBrief)
+	}
+}
+
+// TestBriefRepositoryContext tests the optional repository context section.
+func TestBriefRepositoryContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-repo-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-repo-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	files := map[string]string{
+		"go.mod":              "module example.com/app\n",
+		".gitignore":          "dist/\n*.log\n",
+		"cmd/app/main.go":     "package main\n",
+		"dist/app":            "binary",
+		"debug.log":           "noise",
+		"internal/a/b/c/d.go": "package c\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-test"), []byte("# Phase 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	// Off by default
+	plain, err := brief.Generate(root, dir, "1-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain, "## Repository Context") {
+		t.Error("expected repository context to be opt-in")
+	}
+
+	b, err := brief.BuildWithOptions(root, dir, "1-test", brief.Options{RepoContext: true, TreeDepth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := brief.Render(b, brief.FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fragment := range []string{"## Repository Context", "**Language:** Go", "**Module files:** go.mod", "cmd/", "  app/"} {
+		if !strings.Contains(content, fragment) {
+			t.Errorf("expected repository context to contain %q", fragment)
+		}
+	}
+	for _, fragment := range []string{"dist/", "debug.log", "main.go", ".foreman"} {
+		if strings.Contains(content, fragment) {
+			t.Errorf("expected repository context to exclude %q", fragment)
+		}
+	}
+}
+
+// TestBriefDependencyReports tests that handoff reports of earlier phases reach later briefs.
+func TestBriefDependencyReports(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-reports-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-reports-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	for _, name := range []string{"1-setup", "2-backend", "3-frontend"} {
+		if err := os.WriteFile(project.PhasePlanPath(dir, name), []byte("# "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetPhaseStatus("1-setup", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	report := "## Built\n- CLI skeleton with cobra\n\n## Deviations\n- Used viper instead of flags"
+	if err := project.WritePhaseReport(dir, "1-setup", report); err != nil {
+		t.Fatal(err)
+	}
+	if got := project.ReadPhaseReport(dir, "1-setup"); got != report {
+		t.Errorf("expected stored report to round-trip, got %q", got)
+	}
+
+	briefContent, err := brief.Generate(root, dir, "2-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(briefContent, "### Report: 1-setup") || !strings.Contains(briefContent, "Used viper instead of flags") {
+		t.Error("expected brief to include the dependency's report")
+	}
+
+	// Reports of later phases are not dependencies
+	if err := project.WritePhaseReport(dir, "3-frontend", "Frontend notes"); err != nil {
+		t.Fatal(err)
+	}
+	briefContent, err = brief.Generate(root, dir, "2-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(briefContent, "Frontend notes") {
+		t.Error("expected brief to exclude reports of later phases")
+	}
+}
+
+// TestBriefIncludes tests that include directives are expanded in designs and plans.
+func TestBriefIncludes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-includes-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-includes-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	migration := "-- 001_init.sql\nCREATE TABLE users (\n  id INT PRIMARY KEY\n);\nCREATE INDEX users_id ON users (id);"
+	if err := os.MkdirAll(filepath.Join(root, "db"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "db", "001_init.sql"), []byte(migration), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A design includes a shared snippet, which includes part of the migration
+	snippet := "Users table:\n\n<!-- foreman:include db/001_init.sql#L2-L4 -->"
+	if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), "shared.txt"), []byte(snippet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	design := "# Data Model\n\n<!-- foreman:include .foreman/designs/shared.txt -->"
+	if err := os.WriteFile(filepath.Join(project.DesignsPath(dir), "data.md"), []byte(design), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	designs := project.ReadDesigns(root, dir)
+	if !strings.Contains(designs, "CREATE TABLE users (") || !strings.Contains(designs, "Users table:") {
+		t.Errorf("expected nested includes to be expanded, got:\n%s", designs)
+	}
+	if strings.Contains(designs, "CREATE INDEX") || strings.Contains(designs, "foreman:include") {
+		t.Errorf("expected only the included line range, got:\n%s", designs)
+	}
+
+	// Cycles are reported instead of recursing forever
+	loop := filepath.Join(project.PhasesPath(dir), "1-loop.md")
+	if err := os.WriteFile(loop, []byte("# Loop\n\n<!-- foreman:include .foreman/phases/1-loop.md -->"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if plan := project.ReadPhasePlan(root, dir, "1-loop"); !strings.Contains(plan, "include cycle") {
+		t.Errorf("expected cycle to be marked in the plan, got:\n%s", plan)
+	}
+	if errs := project.CheckIncludes(root, loop); len(errs) != 1 {
+		t.Errorf("expected one include error, got %v", errs)
+	}
+
+	// Paths outside the project root are rejected
+	outside, err := os.MkdirTemp("", "foreman-brief-includes-outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("top secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	escape := filepath.Join(project.PhasesPath(dir), "1-escape.md")
+	include := "../" + filepath.Base(outside) + "/secret.txt"
+	if err := os.WriteFile(escape, []byte("# Escape\n\n<!-- foreman:include "+include+" -->"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if plan := project.ReadPhasePlan(root, dir, "1-escape"); strings.Contains(plan, "top secret") || !strings.Contains(plan, "outside the project root") {
+		t.Errorf("expected include outside the root to be rejected, got:\n%s", plan)
+	}
+	if errs := project.CheckIncludes(root, escape); len(errs) != 1 {
+		t.Errorf("expected one include error, got %v", errs)
+	}
+}
+
+// TestBriefDesignOrder tests that designs are ordered by index.yaml, order frontmatter and path.
+func TestBriefDesignOrder(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-brief-order-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "brief-order-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	designsDir := project.DesignsPath(dir)
+	files := map[string]string{
+		"overview.md":         "# Overview",
+		"backend/api.md":      "---\norder: 2\n---\n# API",
+		"backend/storage.md":  "---\norder: 1\n---\n# Storage",
+		"frontend/ui.md":      "# UI",
+		"frontend/routing.md": "# Routing",
+	}
+	for name, content := range files {
+		path := filepath.Join(designsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func() []string {
+		designs, err := project.LoadDesigns(root, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, d := range designs {
+			names = append(names, d.Name)
+		}
+		return names
+	}
+
+	// order frontmatter first, then the rest by path
+	want := "backend/storage.md backend/api.md frontend/routing.md frontend/ui.md overview.md"
+	if got := strings.Join(names(), " "); got != want {
+		t.Errorf("expected order %q, got %q", want, got)
+	}
+
+	// index.yaml takes precedence
+	index := "order:\n  - overview\n  - frontend/ui.md\n"
+	if err := os.WriteFile(filepath.Join(designsDir, project.DesignIndexFile), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want = "overview.md frontend/ui.md backend/storage.md backend/api.md frontend/routing.md"
+	if got := strings.Join(names(), " "); got != want {
+		t.Errorf("expected index order %q, got %q", want, got)
+	}
+
+	// Headings show the relative path, and directories can be selected
+	plan := "---\ndesigns: [frontend/]\n---\n# Phase 1: UI"
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-ui"), []byte(plan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	briefContent, err := brief.Generate(root, dir, "1-ui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(briefContent, "## frontend/ui.md") || !strings.Contains(briefContent, "## frontend/routing.md") {
+		t.Error("expected brief headings with relative design paths")
+	}
+	if strings.Contains(briefContent, "# Storage") {
+		t.Error("expected backend designs to be omitted from the frontend phase")
+	}
+}
+
+// TestPhaseOrdering tests natural phase ordering and nested phase IDs.
+func TestPhaseOrdering(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-phase-order-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "phase-order-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	overview := "# Phases\n\nTen phases, with the backend split into nested sub-phases for auth and API."
+	if err := os.WriteFile(project.PhaseOverviewPath(dir), []byte(overview), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"10-deploy", "2-backend", "1-setup", "2-backend/1-auth", "2.2-api", "3-frontend"} {
+		path := project.PhasePlanPath(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := project.ListPhaseNames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1-setup 2-backend 2-backend/1-auth 2.2-api 3-frontend 10-deploy"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("expected natural order %q, got %q", want, got)
+	}
+
+	id, err := project.ParsePhaseID("2-backend/1-auth")
+	if err != nil || id.Number() != "2.1" || id.Depth() != 1 {
+		t.Errorf("unexpected nested phase id: %+v (%v)", id, err)
+	}
+	if project.IsPhaseName("setup") || project.IsPhaseName("2-backend/auth") {
+		t.Error("expected names without numbers to be rejected")
+	}
+
+	// State follows the same order, and later phases depend on earlier ones
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Phases[5].Name != "10-deploy" {
+		t.Errorf("expected 10-deploy to be the last phase, got %s", st.Phases[5].Name)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := brief.GenerateAndSave(root, dir, "2-backend/1-auth"); err != nil {
+		t.Fatalf("failed to generate nested phase brief: %v", err)
+	}
+	if _, err := os.Stat(project.BriefPath(dir, "2-backend/1-auth")); err != nil {
+		t.Errorf("expected nested brief to be saved: %v", err)
+	}
+
+	result := gate.ValidatePhases(root, dir)
+	if !result.Passed || !strings.Contains(result.Details[1], "10-deploy.md") {
+		t.Errorf("expected gate to count multi-digit phases: %s %v", result.Message, result.Details)
+	}
+
+	// Plans the parser can't name are reported instead of ignored
+	if err := os.WriteFile(filepath.Join(project.PhasesPath(dir), "notes.md"), []byte("# Notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = gate.ValidatePhases(root, dir)
+	if result.Passed || !strings.Contains(strings.Join(result.Details, " "), "notes.md") {
+		t.Errorf("expected invalid plan name to fail the gate, got %s %v", result.Message, result.Details)
+	}
+}
+
+// TestPhaseTasks tests task parsing, tracking and rollup within phases.
+func TestPhaseTasks(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-tasks-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.Init(tempDir, "tasks-test")
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+
+	plan := "# Phase 1: Backend\n\n## Tasks\n- [ ] Add login endpoint\n- Add session middleware\n  - nested detail, not a task\n1. Write API docs\n\n## Notes\n- not a task either"
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-backend"), []byte(plan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	frontmatterPlan := "---\ntasks: [Configure CI, Configure CI]\n---\n# Phase 2: Ops"
+	if err := os.WriteFile(project.PhasePlanPath(dir, "2-ops"), []byte(frontmatterPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := st.GetPhase("1-backend")
+	var ids []string
+	for _, task := range backend.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if got := strings.Join(ids, " "); got != "add-login-endpoint add-session-middleware write-api-docs" {
+		t.Errorf("unexpected tasks from ## Tasks section: %q", got)
+	}
+	ops := st.GetPhase("2-ops")
+	if len(ops.Tasks) != 2 || ops.Tasks[1].ID != "configure-ci-2" {
+		t.Errorf("unexpected tasks from frontmatter: %+v", ops.Tasks)
+	}
+
+	// Task status survives a re-sync, even when the plan changes
+	if _, err := st.SetTaskStatus("1-backend", "add-login-endpoint", "done"); err != nil {
+		t.Fatal(err)
+	}
+	plan = strings.Replace(plan, "1. Write API docs", "1. Write API docs\n2. Add rate limiting", 1)
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-backend"), []byte(plan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	backend = st.GetPhase("1-backend")
+	if done, total := backend.TaskProgress(); done != 1 || total != 4 {
+		t.Errorf("expected 1/4 tasks done after re-sync, got %d/%d", done, total)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	briefContent, err := brief.Generate(root, dir, "1-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"### Task Progress (1/4 done)", "- [x] Add login endpoint (`add-login-endpoint`, done)", "foreman task 1-backend/<task> done"} {
+		if !strings.Contains(briefContent, want) {
+			t.Errorf("expected brief to contain %q", want)
+		}
+	}
+
+	// Adding a task to a done phase doesn't rewrite its completion record
+	if err := st.SetPhaseStatus("2-ops", "in-progress"); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range []string{"configure-ci", "configure-ci-2"} {
+		if _, err := st.SetTaskStatus("2-ops", task, "done"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops = st.GetPhase("2-ops")
+	ops.EndCommit = "abc123"
+	completedAt := ops.CompletedAt
+	if err := os.WriteFile(project.PhasePlanPath(dir, "2-ops"), []byte("---\ntasks: [Configure CI, Configure CI, Add alerts]\n---\n# Phase 2: Ops"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.SyncPhasesToState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	ops = st.GetPhase("2-ops")
+	if ops.Status != "done" || ops.CompletedAt != completedAt || ops.EndCommit != "abc123" || len(ops.Tasks) != 3 {
+		t.Errorf("expected the done phase to keep its status and history, got %+v", ops)
+	}
+}
+
+// TestScaffolding tests the design, phase and overview scaffolding commands.
+func TestScaffolding(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-scaffold-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.InitWithOptions(tempDir, project.InitOptions{Name: "scaffold-test", Preset: "full", TDD: true})
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Numbering continues after existing phases, including multi-digit ones
+	if err := os.WriteFile(project.PhasePlanPath(dir, "9-polish"), []byte("# Phase 9: Polish"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	name, err := project.NewPhase(dir, "", "Deploy to Production!", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "10-deploy-to-production" {
+		t.Errorf("expected 10-deploy-to-production, got %s", name)
+	}
+	child, err := project.NewPhase(dir, name, "Smoke tests", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child != "10-deploy-to-production/1-smoke-tests" {
+		t.Errorf("unexpected nested phase name %s", child)
+	}
+	plan := project.ReadPhasePlan(root, dir, child)
+	if !strings.Contains(plan, "# Phase 10.1: Smoke tests") || !strings.Contains(plan, "## Tests") {
+		t.Errorf("unexpected plan template:\n%s", plan)
+	}
+	if _, err := project.NewPhase(dir, "", "Deploy to production", cfg); err != nil {
+		t.Errorf("expected a second phase with the same title to get the next number: %v", err)
+	}
+
+	if _, err := project.NewDesign(dir, "backend/data-model", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := project.NewDesign(dir, "backend/data-model.md", cfg); err == nil {
+		t.Error("expected existing design not to be overwritten")
+	}
+	if path, err := project.NewDesign(dir, "élan-vital", cfg); err != nil {
+		t.Fatal(err)
+	} else if content := project.ReadFileContent(path, ""); !strings.HasPrefix(content, "# Élan Vital\n") {
+		t.Errorf("expected a title from the multibyte name, got:\n%s", content)
+	}
+	if _, err := project.NewDesign(dir, "../escape", cfg); err == nil {
+		t.Error("expected design names outside designs/ to be rejected")
+	}
+
+	if _, err := project.NewOverview(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	overview := project.ReadPhaseOverview(root, dir)
+	if !strings.Contains(overview, "10. **Deploy to Production!**") || !strings.Contains(overview, "   10.1. **Smoke tests**") {
+		t.Errorf("expected overview to list the phases:\n%s", overview)
+	}
+
+	// Scaffolds that were never filled in don't pass the gates
+	if result := gate.ValidateDesign(root, dir); result.Passed || result.Message != "Design documents contain placeholder text" {
+		t.Errorf("expected unfilled design scaffold to fail: %s", result.Message)
+	}
+	if result := gate.ValidatePhases(root, dir); result.Passed || result.Message != "Phase overview contains placeholder text" {
+		t.Errorf("expected unfilled overview scaffold to fail: %s", result.Message)
+	}
+	if err := os.WriteFile(project.PhaseOverviewPath(dir), []byte(strings.ReplaceAll(overview, "_", "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := gate.ValidatePhases(root, dir)
+	if result.Passed || result.Message != "Phase plans have no goal" || !strings.Contains(strings.Join(result.Details, " "), "10-deploy-to-production/1-smoke-tests.md") {
+		t.Errorf("expected unfilled phase goals to fail: %s %v", result.Message, result.Details)
+	}
+
+	light := config.NewWithPreset("light", "light")
+	if _, err := project.NewPhase(dir, "", "Anything", light); err == nil {
+		t.Error("expected phases to be refused without a phases stage")
+	}
+}
+
+// TestGeneratePhases tests creating phase plans from the phases listed in the overview.
+func TestGeneratePhases(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "foreman-generate-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, err := project.InitWithOptions(tempDir, project.InitOptions{Name: "generate-test", Preset: "full"})
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	dir := project.ForemanPath(root)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overview := `# Implementation Phases
+
+## Strategy
+
+1. Not a phase: only the phases section is read.
+
+## Phases
+
+1. **Project setup** — Skeleton and CI
+2. Backend API - REST endpoints
+   1. ` + "`2.1-auth`" + ` Auth: Login and sessions
+- Phase 3: Frontend
+
+## Dependencies
+
+1. Backend before frontend
+`
+	if err := os.WriteFile(project.PhaseOverviewPath(dir), []byte(overview), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	listed := project.ParseOverview(overview)
+	var names []string
+	for _, phase := range listed {
+		names = append(names, phase.Name)
+	}
+	if got := strings.Join(names, ","); got != "1-project-setup,2-backend-api,2.1-auth,3-frontend" {
+		t.Fatalf("unexpected phases from list: %s", got)
+	}
+	if listed[0].Goal != "Skeleton and CI" || listed[2].Title != "Auth" || listed[2].Goal != "Login and sessions" {
+		t.Errorf("unexpected titles or goals: %+v", listed)
+	}
+
+	table := `| Phase | Name | Description |
+|:-----:|------|-------------|
+| 1 | **Setup** | Skeleton |
+| 2.1 | Auth | Login |
+| ` + "`10-deploy`" + ` | Deploy | Ship it |`
+	rows := project.ParseOverview(table)
+	if len(rows) != 3 || rows[0].Name != "1-setup" || rows[0].Goal != "Skeleton" || rows[1].Number() != "2.1" || rows[2].Name != "10-deploy" {
+		t.Errorf("unexpected phases from table: %+v", rows)
+	}
+
+	// Existing plans are matched by number and left alone
+	existing := "# Phase 1: Setup\n\nHand-written plan."
+	if err := os.WriteFile(project.PhasePlanPath(dir, "1-setup"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created, err := project.GeneratePhases(root, dir, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(created, ","); got != "2-backend-api,2.1-auth,3-frontend" {
+		t.Errorf("unexpected created phases: %s", got)
+	}
+	if plan := project.ReadPhasePlan(root, dir, "1-setup"); plan != existing {
+		t.Errorf("expected existing plan to be untouched, got:\n%s", plan)
+	}
+	plan := project.ReadPhasePlan(root, dir, "2.1-auth")
+	if !strings.Contains(plan, "# Phase 2.1: Auth") || !strings.Contains(plan, "Login and sessions") {
+		t.Errorf("unexpected generated plan:\n%s", plan)
+	}
+
+	created, err = project.GeneratePhases(root, dir, cfg)
+	if err != nil || len(created) != 0 {
+		t.Errorf("expected a second run to create nothing, got %v (%v)", created, err)
+	}
+
+	// A phase listed without a goal keeps the goal placeholder until it is written
+	result := gate.ValidatePhases(root, dir)
+	if result.Passed || !strings.Contains(strings.Join(result.Details, " "), "3-frontend.md") {
+		t.Errorf("expected the plan without a goal to fail: %s %v", result.Message, result.Details)
+	}
+	frontend, err := os.ReadFile(project.PhasePlanPath(dir, "3-frontend"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filled := strings.Replace(string(frontend), "_One or two sentences: what does this phase deliver?_", "Login and dashboard pages.", 1)
+	if err := os.WriteFile(project.PhasePlanPath(dir, "3-frontend"), []byte(filled), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := gate.ValidatePhases(root, dir); !result.Passed || len(result.Warnings) != 0 {
+		t.Errorf("expected generated phases to pass without warnings: %s %v %v", result.Message, result.Details, result.Warnings)
+	}
+}
+
+// TestInitTemplate tests that every built-in blueprint initializes a project.
+func TestInitTemplate(t *testing.T) {
+	builtin, err := blueprint.Builtin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range builtin {
+		t.Run(b.Name, func(t *testing.T) {
+			root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "acme", Template: b.Name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := project.ForemanPath(root)
+
+			requirements := project.ReadRequirements(root, dir)
+			if !strings.Contains(requirements, "# acme Requirements") {
+				t.Errorf("expected the project name in requirements:\n%s", requirements)
+			}
+			// Blueprint requirements are a skeleton to fill in, not finished requirements
+			if result := gate.ValidateRequirements(root, dir); result.Passed {
+				t.Error("expected blueprint requirements to be flagged as placeholder")
+			}
+			// So are its design skeletons: every design is flagged until its placeholders are replaced
+			designs, err := project.ListDesignFiles(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := gate.ValidateDesign(root, dir)
+			if result.Passed || result.Message != "Design documents contain placeholder text" {
+				t.Errorf("expected blueprint designs to be flagged as placeholder, got %s", result.Message)
+			}
+			for _, name := range designs {
+				if !strings.Contains(strings.Join(result.Details, "\n"), "designs/"+name+":") {
+					t.Errorf("expected designs/%s to be flagged, got %v", name, result.Details)
+				}
+				path := filepath.Join(project.DesignsPath(dir), filepath.FromSlash(name))
+				content := project.ReadFileContent(path, "")
+				// Rewriting the first word of a placeholder is enough to fill it in
+				for _, placeholder := range project.Placeholders(content) {
+					content = strings.Replace(content, strings.Split(placeholder, " ")[0], "Written", 1)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if result := gate.ValidateDesign(root, dir); !result.Passed {
+				t.Errorf("expected filled-in blueprint designs to pass: %s %v", result.Message, result.Details)
+			}
+			if result := gate.ValidatePhases(root, dir); !result.Passed || len(result.Warnings) > 0 {
+				t.Errorf("phases gate: %s %v %v", result.Message, result.Details, result.Warnings)
+			}
+		})
+	}
+
+	// Explicit options win over the blueprint; stages the workflow lacks are skipped
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "svc", Template: "rest-service", Preset: "light", TDD: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := project.ForemanPath(root)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Preset != config.PresetLight || cfg.HasDesignPhase() || !cfg.IsTDDEnabled() {
+		t.Errorf("expected --preset and --tdd to win, got %+v", cfg)
+	}
+	if _, err := os.Stat(project.DesignsPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected no designs/ for a light project")
+	}
+
+	if _, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Template: "unknown"}); err == nil {
+		t.Error("expected an unknown blueprint to fail")
+	}
+}
+
+// TestInitCustomPreset tests initializing projects from user-defined preset files.
+func TestInitCustomPreset(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	presetDir := filepath.Join(xdg, "foreman", "presets")
+	if err := os.MkdirAll(presetDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	preset := `description: Design review, no phase planning
+workflow: [requirements, design, implementation]
+reviewers:
+  overrides:
+    design: human
+auto_advance: 50
+testing:
+  style: coverage
+  min_cover: 75
+`
+	if err := os.WriteFile(filepath.Join(presetDir, "reviewed.yaml"), []byte(preset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(presetDir, "fast.yaml"), []byte("workflow: [requirements, design, implementation]\ngates:\n  skip: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "custom", Preset: "reviewed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := project.ForemanPath(root)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Preset != "reviewed" || !cfg.HasDesignPhase() || cfg.HasPhasesPhase() || cfg.AutoAdvance != 50 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Reviewers.GetReviewer("design") != "human" || cfg.Testing == nil || cfg.Testing.MinCover != 75 {
+		t.Errorf("expected the preset's reviewers and testing, got %+v %+v", cfg.Reviewers, cfg.Testing)
+	}
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "requirements" || strings.Join(st.Workflow, ",") != "requirements,design,implementation" {
+		t.Errorf("unexpected state for the preset's workflow: %+v", st)
+	}
+
+	// Gate rules: skipped gates are approved at init
+	root, err = project.InitWithOptions(t.TempDir(), project.InitOptions{Preset: "fast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err = state.Load(project.ForemanPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "implementation" || st.Gates["design"].Status != "approved" {
+		t.Errorf("expected gates to be skipped, got stage %s", st.CurrentStage)
+	}
+
+	if _, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Preset: "typo"}); err == nil || !strings.Contains(err.Error(), "unknown preset") {
+		t.Errorf("expected unknown presets to be rejected, got %v", err)
+	}
+}
+
+// TestUpdateConfig tests changing settings and the state.yaml updates they cause.
+func TestUpdateConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("FOREMAN_TESTING_FRAMEWORK", "vitest")
+
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "settings", Preset: config.PresetLight})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := project.ForemanPath(root)
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.ApproveGate("requirements", "auto"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	// Settings that don't touch the workflow leave state.yaml alone
+	cfg, changed, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+		return cfg.Set("testing.style", config.TestingStyleTDD)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || !cfg.IsTDDEnabled() {
+		t.Errorf("expected only config.yaml to change, got changed=%v testing=%+v", changed, cfg.Testing)
+	}
+	if cfg.Testing.Framework != "" {
+		t.Error("expected env values not to be written into the project config")
+	}
+
+	// Adding a design stage before implementation sends the project back to design
+	if _, changed, err = project.UpdateConfig(dir, func(cfg *config.Config) error {
+		return cfg.Set("workflow", "requirements,design,implementation")
+	}); err != nil || !changed {
+		t.Fatalf("expected state.yaml to change (%v)", err)
+	}
+	st, err = state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "design" || strings.Join(st.Workflow, ",") != "requirements,design,implementation" {
+		t.Errorf("expected design to be current in the new workflow, got %s %v", st.CurrentStage, st.Workflow)
+	}
+	if errs := st.Validate(); len(errs) != 0 {
+		t.Errorf("expected consistent state, got %v", errs)
+	}
+
+	// Invalid changes write nothing
+	before, err := os.ReadFile(config.ConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+		return cfg.Set("workflow", "design")
+	}); err == nil {
+		t.Error("expected workflow without implementation to be rejected")
+	}
+	after, err := os.ReadFile(config.ConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("expected config.yaml to be unchanged after a rejected change")
+	}
+
+	// Unsetting the workflow falls back to the preset's
+	if _, _, err := project.UpdateConfig(dir, func(cfg *config.Config) error {
+		return cfg.Unset("workflow")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	st, err = state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "implementation" || !st.QuickMode {
+		t.Errorf("expected light workflow at implementation, got %s %v", st.CurrentStage, st.Workflow)
+	}
+}
+
+// TestUpgradePreset tests planning and applying a switch to another preset.
+func TestUpgradePreset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "upgrade", Preset: config.PresetLight})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := project.ForemanPath(root)
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.ApproveGate("requirements", "human"); err != nil {
+		t.Fatal(err)
+	}
+	st.AddPhase("1-setup")
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(config.ConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.ConfigPath(dir), append([]byte("# team project\n"), data...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := project.PlanUpgrade(root, dir, config.PresetLight); err == nil {
+		t.Error("expected upgrading to the current preset to fail")
+	}
+
+	// Planning touches nothing
+	u, err := project.PlanUpgrade(root, dir, config.PresetFull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.From != config.PresetLight || u.To != config.PresetFull || u.State.CurrentStage != "design" {
+		t.Errorf("expected light → full with design current, got %s → %s at %s", u.From, u.To, u.State.CurrentStage)
+	}
+	if len(u.Dirs) != 2 {
+		t.Errorf("expected designs/ and phases/ to be created, got %v", u.Dirs)
+	}
+	if _, err := os.Stat(project.DesignsPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected PlanUpgrade not to create directories")
+	}
+	if st, _ := state.Load(dir); st.CurrentStage != "implementation" {
+		t.Errorf("expected PlanUpgrade not to change state.yaml, got %s", st.CurrentStage)
+	}
+
+	if err := u.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err = state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Preset != config.PresetFull || !cfg.HasDesignPhase() || !cfg.HasPhasesPhase() {
+		t.Errorf("expected the full preset in config.yaml, got %+v", cfg)
+	}
+	if st.CurrentStage != "design" || st.Gates["requirements"].Status != "approved" || st.Gates["design"].Status != "open" {
+		t.Errorf("expected approved requirements and open design gate, got %s %+v", st.CurrentStage, st.Gates)
+	}
+	if len(st.Phases) != 1 || st.Phases[0].Name != "1-setup" {
+		t.Errorf("expected phases to be carried over, got %+v", st.Phases)
+	}
+	for _, path := range []string{project.DesignsPath(dir), project.PhasesPath(dir)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+	if data, _ := os.ReadFile(config.ConfigPath(dir)); !strings.HasPrefix(string(data), "# team project\n") {
+		t.Error("expected comments in config.yaml to be preserved")
+	}
+
+	// Downgrading to minimal approves everything before implementation
+	u, err = project.PlanUpgrade(root, dir, config.PresetMinimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	st, err = state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "implementation" || !st.MinimalMode || len(st.Gates) != 2 {
+		t.Errorf("expected minimal state at implementation, got %+v", st)
+	}
+	if errs := st.Validate(); len(errs) != 0 {
+		t.Errorf("expected consistent state, got %v", errs)
+	}
+	cfg, err = config.LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Reviewers.Overrides["requirements"]; ok {
+		t.Errorf("expected the full preset's reviewer overrides to be dropped, got %v", cfg.Reviewers.Overrides)
+	}
+}
+
+// TestTracks tests creating a named track next to the main one and selecting it with default_track.
+func TestTracks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "monolith", Preset: config.PresetFull, TechStack: []string{"go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainDir := project.ForemanPath(root)
+	if _, _, err := project.UpdateConfig(mainDir, func(cfg *config.Config) error {
+		return cfg.Set("git.tags", "true")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if track, err := config.ResolveTrack(root, ""); err != nil || track != config.MainTrack {
+		t.Errorf("expected the main track by default, got %q (%v)", track, err)
+	}
+
+	// Creating the track leaves the main flow alone
+	if _, err := project.InitWithOptions(root, project.InitOptions{Preset: config.PresetLight, Track: "billing"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := project.InitWithOptions(root, project.InitOptions{Preset: config.PresetLight, Track: "billing"}); err == nil {
+		t.Error("expected creating an existing track to fail")
+	}
+	dir := config.TrackDir(root, "billing")
+	if dir != filepath.Join(root, ".foreman", "tracks", "billing") || !config.TrackExists(root, "billing") {
+		t.Errorf("expected the track in .foreman/tracks/billing, got %s", dir)
+	}
+	for _, file := range []string{"config.yaml", "state.yaml", "requirements.md"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("expected track file %s: %v", file, err)
+		}
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "monolith" || strings.Join(cfg.TechStack, ",") != "go" || cfg.Preset != config.PresetLight {
+		t.Errorf("expected the track to share the project's name and tech stack, got %+v", cfg)
+	}
+	if cfg.Git == nil || !cfg.Git.Tags || cfg.Git.TagPrefix != "" {
+		t.Errorf("expected the track to share the git integration, got %+v", cfg.Git)
+	}
+	if tag := cfg.Git.GateTag("billing", "requirements"); tag != "foreman/billing/requirements-approved" {
+		t.Errorf("expected track-specific gate tags, got %s", tag)
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.ApproveGate("requirements", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+
+	main, err := state.Load(mainDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if main.CurrentStage != "requirements" || len(main.GetActiveStages()) != 4 {
+		t.Errorf("expected the main track to be unchanged, got %s %v", main.CurrentStage, main.GetActiveStages())
+	}
+
+	// default_track selects the track when --track isn't given
+	if _, _, err := project.UpdateConfig(mainDir, func(cfg *config.Config) error {
+		return cfg.Set("default_track", "billing")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if track, err := config.ResolveTrack(root, ""); err != nil || track != "billing" {
+		t.Errorf("expected the default track, got %q (%v)", track, err)
+	}
+	if track, err := config.ResolveTrack(root, config.MainTrack); err != nil || track != config.MainTrack {
+		t.Errorf("expected --track to override the default track, got %q (%v)", track, err)
+	}
+
+	// A track can use the project's own presets
+	presetDir := filepath.Join(mainDir, "presets")
+	if err := os.MkdirAll(presetDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(presetDir, "team.yaml"), []byte("workflow: [requirements, design, implementation]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := project.InitWithOptions(root, project.InitOptions{Preset: "team", Track: "ops"}); err != nil {
+		t.Fatalf("expected the project preset to be found, got %v", err)
+	}
+	if cfg, err := config.Load(config.TrackDir(root, "ops")); err != nil || cfg.Preset != "team" {
+		t.Errorf("expected the ops track to use the team preset, got %+v (%v)", cfg, err)
+	}
+
+	tracks, err := config.ListTracks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tracks, ",") != "billing,ops" {
+		t.Errorf("expected two named tracks, got %v", tracks)
+	}
+}
@@ -0,0 +1,194 @@
+// Package blueprint provides project blueprints for 'foreman init --template':
+// starting requirements, designs and phase plans plus config overrides.
+//
+// A blueprint is a directory laid out like .foreman/:
+//
+//	blueprint.yaml     description and config overrides
+//	requirements.md
+//	designs/*.md
+//	phases/overview.md, phases/<N>-<name>.md
+//
+// "{{name}}" in any document is replaced with the project name.
+package blueprint
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed builtin
+var builtinFS embed.FS
+
+// ManifestFile describes a blueprint and holds its config overrides.
+const ManifestFile = "blueprint.yaml"
+
+// NameVariable is replaced with the project name in blueprint documents.
+const NameVariable = "{{name}}"
+
+// Manifest is the schema of blueprint.yaml.
+type Manifest struct {
+	Description string    `yaml:"description"`
+	Config      yaml.Node `yaml:"config"` // any config.yaml fields, applied over the preset
+}
+
+// Blueprint is a loaded project blueprint.
+type Blueprint struct {
+	Name        string
+	Description string
+	config      yaml.Node
+	fsys        fs.FS
+}
+
+// File is a document to create in .foreman/.
+type File struct {
+	Path    string // slash-separated path within .foreman/, e.g. "designs/api.md"
+	Content string
+}
+
+// Builtin returns the built-in blueprints sorted by name.
+func Builtin() ([]*Blueprint, error) {
+	entries, err := fs.ReadDir(builtinFS, "builtin")
+	if err != nil {
+		return nil, err
+	}
+
+	var blueprints []*Blueprint
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sub, err := fs.Sub(builtinFS, path.Join("builtin", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		b, err := load(entry.Name(), sub)
+		if err != nil {
+			return nil, err
+		}
+		blueprints = append(blueprints, b)
+	}
+	sort.Slice(blueprints, func(i, j int) bool { return blueprints[i].Name < blueprints[j].Name })
+	return blueprints, nil
+}
+
+// Load loads a blueprint by built-in name, or from a directory when ref is a path.
+func Load(ref string) (*Blueprint, error) {
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return load(path.Base(strings.TrimRight(ref, "/")), os.DirFS(ref))
+	}
+	if strings.ContainsAny(ref, `/\`) || strings.HasPrefix(ref, ".") {
+		return nil, fmt.Errorf("blueprint directory %s not found", ref)
+	}
+
+	builtin, err := Builtin()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, b := range builtin {
+		if b.Name == ref {
+			return b, nil
+		}
+		names = append(names, b.Name)
+	}
+	return nil, fmt.Errorf("unknown blueprint %q (built-in: %s, or a path to a blueprint directory)", ref, strings.Join(names, ", "))
+}
+
+// load reads a blueprint's manifest. The manifest is optional.
+func load(name string, fsys fs.FS) (*Blueprint, error) {
+	b := &Blueprint{Name: name, fsys: fsys}
+
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of blueprint %s: %w", ManifestFile, name, err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of blueprint %s: %w", ManifestFile, name, err)
+	}
+	b.Description = m.Description
+	b.config = m.Config
+	return b, nil
+}
+
+// Preset returns the preset the blueprint's config asks for, if any.
+func (b *Blueprint) Preset() string {
+	var c struct {
+		Preset string `yaml:"preset"`
+	}
+	if b.config.Kind != 0 {
+		_ = b.config.Decode(&c)
+	}
+	return c.Preset
+}
+
+// ApplyConfig applies the blueprint's config overrides to cfg.
+func (b *Blueprint) ApplyConfig(cfg *config.Config) error {
+	if b.config.Kind == 0 {
+		return nil
+	}
+	if err := b.config.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid config in blueprint %s: %w", b.Name, err)
+	}
+
+	if cfg.Reviewers.Overrides == nil {
+		cfg.Reviewers.Overrides = make(map[string]string)
+	}
+	if len(cfg.Workflow) > 0 {
+		if err := config.ValidateWorkflow(cfg.Workflow); err != nil {
+			return fmt.Errorf("invalid config in blueprint %s: %w", b.Name, err)
+		}
+	}
+	for stage, reviewer := range cfg.Reviewers.Overrides {
+		if reviewer != "auto" && reviewer != "human" {
+			return fmt.Errorf("invalid config in blueprint %s: reviewer for %s must be 'auto' or 'human', not %q", b.Name, stage, reviewer)
+		}
+	}
+	return nil
+}
+
+// Files returns the blueprint's documents with the project name substituted:
+// requirements.md and everything under designs/ and phases/. Other files are ignored.
+func (b *Blueprint) Files(projectName string) ([]File, error) {
+	var files []File
+	err := fs.WalkDir(b.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if p != "requirements.md" && !strings.HasPrefix(p, "designs/") && !strings.HasPrefix(p, "phases/") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(b.fsys, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{
+			Path:    p,
+			Content: strings.ReplaceAll(string(data), NameVariable, projectName),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blueprint %s: %w", b.Name, err)
+	}
+	return files, nil
+}
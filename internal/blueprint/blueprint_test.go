@@ -0,0 +1,125 @@
+package blueprint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+)
+
+func TestBuiltin(t *testing.T) {
+	builtin, err := Builtin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, b := range builtin {
+		names = append(names, b.Name)
+		if b.Description == "" {
+			t.Errorf("blueprint %s has no description", b.Name)
+		}
+
+		cfg := config.NewWithPreset("demo", b.Preset())
+		if err := b.ApplyConfig(cfg); err != nil {
+			t.Errorf("blueprint %s: %v", b.Name, err)
+		}
+
+		files, err := b.Files("demo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		hasRequirements := false
+		for _, f := range files {
+			if f.Path == "requirements.md" {
+				hasRequirements = true
+			}
+			if strings.Contains(f.Content, NameVariable) {
+				t.Errorf("blueprint %s: %s still contains %s", b.Name, f.Path, NameVariable)
+			}
+		}
+		if !hasRequirements {
+			t.Errorf("blueprint %s has no requirements.md", b.Name)
+		}
+	}
+
+	if strings.Join(names, ",") != "go-cli,library,rest-service" {
+		t.Errorf("unexpected built-in blueprints: %v", names)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "team-service")
+	files := map[string]string{
+		ManifestFile:         "description: Team default\nconfig:\n  preset: light\n  tech_stack: [Rust]\n  reviewers:\n    overrides:\n      requirements: human\n",
+		"requirements.md":    "# {{name}}\n",
+		"designs/api.md":     "# {{name}} API\n",
+		"notes/ignored.md":   "not copied",
+		".git/config":        "not copied",
+		"phases/1-setup.md":  "# Phase 1: Setup of {{name}}\n",
+		"phases/overview.md": "# Phases\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Name != "team-service" || b.Description != "Team default" || b.Preset() != "light" {
+		t.Errorf("unexpected blueprint: %+v", b)
+	}
+
+	cfg := config.NewWithPreset("svc", "light")
+	if err := b.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.TechStack) != 1 || cfg.TechStack[0] != "Rust" || cfg.Reviewers.GetReviewer("requirements") != "human" || cfg.AutoAdvance != 70 {
+		t.Errorf("expected overrides on top of the preset, got %+v", cfg)
+	}
+
+	got, err := b.Files("svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range got {
+		paths = append(paths, f.Path)
+		if f.Path == "designs/api.md" && f.Content != "# svc API\n" {
+			t.Errorf("expected the project name to be substituted, got %q", f.Content)
+		}
+	}
+	if strings.Join(paths, ",") != "designs/api.md,phases/1-setup.md,phases/overview.md,requirements.md" {
+		t.Errorf("unexpected files: %v", paths)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load("no-such-blueprint"); err == nil || !strings.Contains(err.Error(), "go-cli") {
+		t.Errorf("expected unknown blueprint error listing the built-ins, got %v", err)
+	}
+	if _, err := Load("./no-such-dir"); err == nil {
+		t.Error("expected missing directory to fail")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte("config:\n  workflow: [design]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ApplyConfig(config.NewDefault("x")); err == nil {
+		t.Error("expected an invalid workflow override to fail")
+	}
+}
@@ -3,6 +3,7 @@ package brief
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,161 +12,289 @@ import (
 	"github.com/thinkshake/foreman/internal/state"
 )
 
-// Generate creates a self-contained brief for a phase.
-func Generate(root, phaseName string) (string, error) {
+// Generate creates a self-contained brief for a phase of the track in dir
+// (see config.TrackDir) of the project at root.
+func Generate(root, dir, phaseName string) (string, error) {
+	b, err := Build(root, dir, phaseName)
+	if err != nil {
+		return "", err
+	}
+	return Render(b, FormatMarkdown)
+}
+
+// Options controls the output format and optional sections of a brief.
+type Options struct {
+	Format      string // one of Formats (default: markdown)
+	RepoContext bool   // include the Repository Context section
+	TreeDepth   int    // file tree depth for the repository context (default: DefaultTreeDepth)
+}
+
+// treeDepth returns the configured tree depth or the default.
+func (o Options) treeDepth() int {
+	if o.TreeDepth > 0 {
+		return o.TreeDepth
+	}
+	return DefaultTreeDepth
+}
+
+// Build assembles the brief model for a phase.
+func Build(root, dir, phaseName string) (*Brief, error) {
+	return BuildWithOptions(root, dir, phaseName, Options{})
+}
+
+// BuildWithOptions assembles the brief model for a phase, including optional sections.
+func BuildWithOptions(root, dir, phaseName string, opts Options) (*Brief, error) {
 	// Load project config
-	cfg, err := config.Load(root)
+	cfg, err := config.Load(dir)
 	if err != nil {
-		return "", fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Load state
-	st, err := state.Load(root)
+	st, err := state.Load(dir)
 	if err != nil {
-		return "", fmt.Errorf("failed to load state: %w", err)
+		return nil, fmt.Errorf("failed to load state: %w", err)
 	}
 
 	// Find the target phase
 	targetPhase := st.GetPhase(phaseName)
 	if targetPhase == nil {
-		return "", fmt.Errorf("phase %q not found", phaseName)
+		return nil, fmt.Errorf("phase %q not found", phaseName)
 	}
 
 	// Read content files
-	requirements := project.ReadRequirements(root)
-	designs := project.ReadDesigns(root)
-	phaseOverview := project.ReadPhaseOverview(root)
-	phasePlan := project.ReadPhasePlan(root, phaseName)
-
-	// Build the brief
-	var b strings.Builder
+	requirements := project.ReadRequirements(root, dir)
+	designs, err := phaseDesignContext(root, dir, phaseName)
+	if err != nil {
+		return nil, err
+	}
+	phaseOverview := project.ReadPhaseOverview(root, dir)
+	phasePlan := project.ReadPhasePlan(root, dir, phaseName)
 
 	// Header
-	b.WriteString(fmt.Sprintf("# Phase Brief: %s\n\n", phaseName))
-	b.WriteString(fmt.Sprintf("**Generated:** %s\n", getCurrentTimestamp()))
-	b.WriteString(fmt.Sprintf("**Status:** %s\n\n", targetPhase.Status))
+	b := &Brief{
+		Kind:    "phase",
+		Title:   fmt.Sprintf("Phase Brief: %s", phaseName),
+		Phase:   phaseName,
+		Project: cfg.Name,
+	}
+	b.AddMeta("generated", "Generated", getCurrentTimestamp())
+	b.AddMeta("status", "Status", targetPhase.Status)
 
 	// Project Context
-	b.WriteString("## Project Context\n\n")
-	b.WriteString(fmt.Sprintf("**Name:** %s\n", cfg.Name))
-	if cfg.Description != "" {
-		b.WriteString(fmt.Sprintf("**Description:** %s\n", cfg.Description))
-	}
-	if len(cfg.TechStack) > 0 {
-		b.WriteString(fmt.Sprintf("**Tech Stack:** %s\n", strings.Join(cfg.TechStack, ", ")))
+	b.AddSection("project-context", "Project Context", projectContext(cfg))
+
+	// Repository Context (optional)
+	precedingPhases := getPrecedingPhases(st.Phases, phaseName)
+	if opts.RepoContext {
+		b.AddSection("repository-context", "Repository Context", repositoryContext(root, opts.treeDepth(), lastCompleted(precedingPhases)))
 	}
-	b.WriteString("\n")
 
 	// Requirements
-	b.WriteString("## Requirements\n\n")
-	b.WriteString(requirements)
-	b.WriteString("\n\n")
+	b.AddSection("requirements", "Requirements", requirements)
 
 	// Design Context
-	b.WriteString("## Design Context\n\n")
-	b.WriteString(designs)
-	b.WriteString("\n\n")
+	b.AddSection("design-context", "Design Context", designs)
 
 	// Phase Overview
-	b.WriteString("## Phase Overview\n\n")
-	b.WriteString(phaseOverview)
-	b.WriteString("\n\n")
+	b.AddSection("phase-overview", "Phase Overview", phaseOverview)
 
 	// Dependencies - show status of preceding phases
-	b.WriteString("## Dependencies\n\n")
-	precedingPhases := getPrecedingPhases(st.Phases, phaseName)
-	if len(precedingPhases) > 0 {
-		for _, phase := range precedingPhases {
-			status := phase.Status
-			indicator := getStatusIndicator(status)
-			b.WriteString(fmt.Sprintf("- **%s**: %s `%s`\n", phase.Name, indicator, status))
+	var deps strings.Builder
+	if len(precedingPhases) == 0 {
+		deps.WriteString("_No dependencies - this is the first phase._\n")
+	}
+	for _, phase := range precedingPhases {
+		status := phase.Status
+		indicator := getStatusIndicator(status)
+		deps.WriteString(fmt.Sprintf("- **%s**: %s `%s`\n", phase.Name, indicator, status))
+	}
+	dependencies := b.AddSection("dependencies", "Dependencies", deps.String())
+
+	// Check for blockers
+	var warnings strings.Builder
+	for _, phase := range precedingPhases {
+		if phase.Status != "done" {
+			warnings.WriteString(fmt.Sprintf("- Phase **%s** is `%s` (not done yet)\n", phase.Name, phase.Status))
 		}
-		
-		// Check for blockers
-		hasBlockers := false
-		for _, phase := range precedingPhases {
-			if phase.Status != "done" {
-				if !hasBlockers {
-					b.WriteString("\n### ⚠️  Dependency Warnings\n\n")
-					hasBlockers = true
-				}
-				b.WriteString(fmt.Sprintf("- Phase **%s** is `%s` (not done yet)\n", phase.Name, phase.Status))
-			}
+	}
+	if warnings.Len() > 0 {
+		dependencies.AddSection("dependency-warnings", "⚠️  Dependency Warnings", warnings.String())
+	}
+
+	// Handoff reports written when dependency phases were completed
+	for _, phase := range precedingPhases {
+		if report := project.ReadPhaseReport(dir, phase.Name); report != "" {
+			dependencies.AddSection("report-"+phase.Name, fmt.Sprintf("Report: %s", phase.Name), report)
 		}
-	} else {
-		b.WriteString("_No dependencies - this is the first phase._\n")
 	}
-	b.WriteString("\n")
 
 	// This Phase Spec
-	b.WriteString(fmt.Sprintf("## Phase Spec: %s\n\n", phaseName))
-	b.WriteString(phasePlan)
-	b.WriteString("\n\n")
+	spec := b.AddSection("phase-spec", fmt.Sprintf("Phase Spec: %s", phaseName), phasePlan)
+	if done, total := targetPhase.TaskProgress(); total > 0 {
+		var tasks strings.Builder
+		for _, task := range targetPhase.Tasks {
+			check := " "
+			if task.Status == "done" {
+				check = "x"
+			}
+			tasks.WriteString(fmt.Sprintf("- [%s] %s (`%s`, %s)\n", check, task.Title, task.ID, task.Status))
+		}
+		spec.AddSection("tasks", fmt.Sprintf("Task Progress (%d/%d done)", done, total), tasks.String())
+	}
 
 	// TDD Instructions (if enabled)
 	if cfg.IsTDDEnabled() {
-		b.WriteString("## Test-Driven Development\n\n")
-		b.WriteString("⚠️ **TDD is enabled for this project.** Follow this workflow:\n\n")
-		b.WriteString("1. **Write tests first** — Define expected behavior before implementation\n")
-		b.WriteString("2. **Run tests (they should fail)** — Confirm the test is valid\n")
-		b.WriteString("3. **Implement the feature** — Write minimal code to pass the test\n")
-		b.WriteString("4. **Refactor** — Clean up while keeping tests green\n")
-		b.WriteString("5. **Repeat** — For each feature/function\n\n")
-		if cfg.Testing.Framework != "" {
-			b.WriteString(fmt.Sprintf("**Testing framework:** %s\n\n", cfg.Testing.Framework))
-		}
-		if cfg.Testing.Required {
-			b.WriteString("**⚠️ Tests are required** — Phase cannot be marked complete without passing tests.\n\n")
-		}
+		b.AddSection("tdd", "Test-Driven Development", tddInstructions(cfg))
 	}
 
 	// Implementation Guidelines
-	b.WriteString("## Implementation Guidelines\n\n")
-	b.WriteString(fmt.Sprintf("- This phase is currently: **%s**\n", targetPhase.Status))
+	var guide strings.Builder
+	guide.WriteString(fmt.Sprintf("- This phase is currently: **%s**\n", targetPhase.Status))
 	if targetPhase.Status == "planned" {
-		b.WriteString("- Ready to start implementation\n")
+		guide.WriteString("- Ready to start implementation\n")
 	} else if targetPhase.Status == "in-progress" {
-		b.WriteString("- Implementation is ongoing\n")
+		guide.WriteString("- Implementation is ongoing\n")
 	} else if targetPhase.Status == "done" {
-		b.WriteString("- This phase is marked as completed\n")
+		guide.WriteString("- This phase is marked as completed\n")
 	}
 	if cfg.IsTDDEnabled() {
-		b.WriteString("- **Write tests first** (TDD enabled)\n")
+		guide.WriteString("- **Write tests first** (TDD enabled)\n")
 	}
+	guidelines := b.AddSection("guidelines", "Implementation Guidelines", guide.String())
 
 	// Show context about other phases for awareness
-	b.WriteString("\n### Related Phases\n\n")
+	var related strings.Builder
 	for _, phase := range st.Phases {
 		if phase.Name == phaseName {
 			continue
 		}
 		indicator := getStatusIndicator(phase.Status)
-		b.WriteString(fmt.Sprintf("- %s %s (`%s`)\n", indicator, phase.Name, phase.Status))
+		related.WriteString(fmt.Sprintf("- %s %s (`%s`)\n", indicator, phase.Name, phase.Status))
 	}
+	guidelines.AddSection("related-phases", "Related Phases", related.String())
 
 	// Completion criteria
-	b.WriteString("\n### Completion\n\n")
-	b.WriteString("When this phase is complete:\n")
-	b.WriteString(fmt.Sprintf("- Run `foreman phase %s done` to mark it as finished\n", phaseName))
-	b.WriteString("- Ensure all deliverables are implemented and tested\n")
-	b.WriteString("- Document any changes or decisions made during implementation\n")
+	var completion strings.Builder
+	completion.WriteString("When this phase is complete:\n")
+	if len(targetPhase.Tasks) > 0 {
+		completion.WriteString(fmt.Sprintf("- Run `foreman task %s/<task> done` after each task; the phase is marked done with its last task\n", phaseName))
+	} else {
+		completion.WriteString(fmt.Sprintf("- Run `foreman phase %s done` to mark it as finished\n", phaseName))
+	}
+	completion.WriteString("- Ensure all deliverables are implemented and tested\n")
+	completion.WriteString("- Document any changes or decisions made during implementation\n")
+	guidelines.AddSection("completion", "Completion", completion.String())
 
-	return b.String(), nil
+	return b, nil
+}
+
+// projectContext renders the project name, description and tech stack.
+func projectContext(cfg *config.Config) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("**Name:** %s\n", cfg.Name))
+	if cfg.Description != "" {
+		b.WriteString(fmt.Sprintf("**Description:** %s\n", cfg.Description))
+	}
+	if len(cfg.TechStack) > 0 {
+		b.WriteString(fmt.Sprintf("**Tech Stack:** %s\n", strings.Join(cfg.TechStack, ", ")))
+	}
+	return b.String()
+}
+
+// tddInstructions renders the test-driven development workflow.
+func tddInstructions(cfg *config.Config) string {
+	var b strings.Builder
+	b.WriteString("⚠️ **TDD is enabled for this project.** Follow this workflow:\n\n")
+	b.WriteString("1. **Write tests first** — Define expected behavior before implementation\n")
+	b.WriteString("2. **Run tests (they should fail)** — Confirm the test is valid\n")
+	b.WriteString("3. **Implement the feature** — Write minimal code to pass the test\n")
+	b.WriteString("4. **Refactor** — Clean up while keeping tests green\n")
+	b.WriteString("5. **Repeat** — For each feature/function\n")
+	if cfg.Testing.Framework != "" {
+		b.WriteString(fmt.Sprintf("\n**Testing framework:** %s\n", cfg.Testing.Framework))
+	}
+	if cfg.Testing.Required {
+		b.WriteString("\n**⚠️ Tests are required** — Phase cannot be marked complete without passing tests.\n")
+	}
+	return b.String()
 }
 
 // GenerateAndSave creates a brief and saves it to the briefs directory.
-func GenerateAndSave(root, phaseName string) (string, error) {
-	brief, err := Generate(root, phaseName)
+func GenerateAndSave(root, dir, phaseName string) (string, error) {
+	return GenerateFormatAndSave(root, dir, phaseName, FormatMarkdown)
+}
+
+// GenerateFormatAndSave creates a brief in the given format and saves it to the briefs directory.
+func GenerateFormatAndSave(root, dir, phaseName, format string) (string, error) {
+	return GenerateWithOptionsAndSave(root, dir, phaseName, Options{Format: format})
+}
+
+// GenerateWithOptionsAndSave creates a brief with the given options and saves it.
+func GenerateWithOptionsAndSave(root, dir, phaseName string, opts Options) (string, error) {
+	b, err := BuildWithOptions(root, dir, phaseName, opts)
 	if err != nil {
 		return "", err
 	}
+	return renderAndSave(dir, phaseName, opts.Format, b)
+}
 
-	briefPath := project.BriefPath(root, phaseName)
-	if err := os.WriteFile(briefPath, []byte(brief), 0644); err != nil {
+// renderAndSave renders a brief and writes it to its format-specific path.
+func renderAndSave(dir, name, format string, b *Brief) (string, error) {
+	content, err := Render(b, format)
+	if err != nil {
+		return "", err
+	}
+
+	// Nested phases ("2-backend/1-auth") are saved in subdirectories
+	path := Path(dir, name, format)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create briefs directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return "", fmt.Errorf("failed to write brief: %w", err)
 	}
 
-	return brief, nil
+	return content, nil
+}
+
+// phaseDesignContext renders the design documents selected by the phase's
+// frontmatter, followed by a note naming any documents that were left out.
+func phaseDesignContext(root, dir, phaseName string) (string, error) {
+	meta, err := project.ReadPhaseMeta(dir, phaseName)
+	if err != nil {
+		return "", err
+	}
+
+	designs, err := project.LoadDesigns(root, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to load designs: %w", err)
+	}
+	if !meta.SelectsDesigns() {
+		if len(designs) == 0 {
+			return "_No design documents with content found._", nil
+		}
+		return project.FormatDesigns(designs), nil
+	}
+
+	included, omitted := project.SelectDesigns(designs, meta)
+
+	var b strings.Builder
+	if len(included) > 0 {
+		b.WriteString(project.FormatDesigns(included))
+	} else {
+		b.WriteString("_No design documents selected for this phase._")
+	}
+
+	if len(omitted) > 0 {
+		names := make([]string, len(omitted))
+		for i, d := range omitted {
+			names[i] = d.Name
+		}
+		b.WriteString(fmt.Sprintf("\n\n_Omitted design documents (not selected for this phase): %s_", strings.Join(names, ", ")))
+	}
+
+	return b.String(), nil
 }
 
 // getPrecedingPhases returns all phases that come before the target phase.
@@ -202,122 +331,131 @@ func getCurrentTimestamp() string {
 }
 
 // GenerateQuickBrief creates a streamlined brief for quick mode.
-func GenerateQuickBrief(root, task string) (string, error) {
+func GenerateQuickBrief(root, dir, task string) (string, error) {
+	b, err := BuildQuick(root, dir, task)
+	if err != nil {
+		return "", err
+	}
+	return Render(b, FormatMarkdown)
+}
+
+// BuildQuick assembles the brief model for quick mode.
+func BuildQuick(root, dir, task string) (*Brief, error) {
+	return BuildQuickWithOptions(root, dir, task, Options{})
+}
+
+// BuildQuickWithOptions assembles the quick mode brief model, including optional sections.
+func BuildQuickWithOptions(root, dir, task string, opts Options) (*Brief, error) {
 	// Load project config
-	cfg, err := config.Load(root)
+	cfg, err := config.Load(dir)
 	if err != nil {
-		return "", fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Read requirements (which contains the task details)
-	requirements := project.ReadRequirements(root)
-
-	// Build the brief
-	var b strings.Builder
+	requirements := project.ReadRequirements(root, dir)
 
 	// Header
-	b.WriteString("# Implementation Brief\n\n")
-	b.WriteString(fmt.Sprintf("**Project:** %s\n", cfg.Name))
-	b.WriteString(fmt.Sprintf("**Generated:** %s\n", getCurrentTimestamp()))
+	b := &Brief{
+		Kind:    "quick",
+		Title:   "Implementation Brief",
+		Phase:   "impl",
+		Project: cfg.Name,
+	}
+	b.AddMeta("project", "Project", cfg.Name)
+	b.AddMeta("generated", "Generated", getCurrentTimestamp())
 
 	// Mode indicator
 	presetName := config.NormalizePreset(cfg.Preset)
 	switch presetName {
 	case config.PresetMinimal:
-		b.WriteString("**Mode:** Minimal (no gates)\n")
+		b.AddMeta("mode", "Mode", "Minimal (no gates)")
 	case config.PresetLight:
-		b.WriteString("**Mode:** Light (requirements gate only)\n")
+		b.AddMeta("mode", "Mode", "Light (requirements gate only)")
 	default:
-		b.WriteString("**Mode:** Quick (no design/phases)\n")
+		b.AddMeta("mode", "Mode", "Quick (no design/phases)")
 	}
 
 	// TDD indicator
 	if cfg.IsTDDEnabled() {
-		b.WriteString("**Testing:** TDD enabled")
+		testingInfo := "TDD enabled"
 		if cfg.Testing.Framework != "" {
-			b.WriteString(fmt.Sprintf(" (%s)", cfg.Testing.Framework))
+			testingInfo += fmt.Sprintf(" (%s)", cfg.Testing.Framework)
 		}
-		b.WriteString("\n")
+		b.AddMeta("testing", "Testing", testingInfo)
 	}
-	b.WriteString("\n")
 
 	// Task
-	b.WriteString("## Task\n\n")
-	b.WriteString(task)
-	b.WriteString("\n\n")
+	b.AddSection("task", "Task", task)
+
+	// Repository Context (optional)
+	if opts.RepoContext {
+		b.AddSection("repository-context", "Repository Context", repositoryContext(root, opts.treeDepth(), nil))
+	}
 
 	// Requirements
-	b.WriteString("## Requirements\n\n")
-	b.WriteString(requirements)
-	b.WriteString("\n\n")
+	b.AddSection("requirements", "Requirements", requirements)
 
 	// Tech Stack (if specified)
 	if len(cfg.TechStack) > 0 {
-		b.WriteString("## Tech Stack\n\n")
-		for _, tech := range cfg.TechStack {
-			b.WriteString(fmt.Sprintf("- %s\n", tech))
+		var tech strings.Builder
+		for _, t := range cfg.TechStack {
+			tech.WriteString(fmt.Sprintf("- %s\n", t))
 		}
-		b.WriteString("\n")
+		b.AddSection("tech-stack", "Tech Stack", tech.String())
 	}
 
 	// TDD Instructions (if enabled)
 	if cfg.IsTDDEnabled() {
-		b.WriteString("## Test-Driven Development\n\n")
-		b.WriteString("⚠️ **TDD is enabled for this project.** Follow this workflow:\n\n")
-		b.WriteString("1. **Write tests first** — Define expected behavior before implementation\n")
-		b.WriteString("2. **Run tests (they should fail)** — Confirm the test is valid\n")
-		b.WriteString("3. **Implement the feature** — Write minimal code to pass the test\n")
-		b.WriteString("4. **Refactor** — Clean up while keeping tests green\n")
-		b.WriteString("5. **Repeat** — For each feature/function\n\n")
-		if cfg.Testing.Framework != "" {
-			b.WriteString(fmt.Sprintf("**Testing framework:** %s\n\n", cfg.Testing.Framework))
-		}
-		if cfg.Testing.Required {
-			b.WriteString("**⚠️ Tests are required** — Phase cannot be marked complete without passing tests.\n\n")
-		}
+		b.AddSection("tdd", "Test-Driven Development", tddInstructions(cfg))
 	}
 
 	// Implementation Guidelines
-	b.WriteString("## Implementation Guidelines\n\n")
+	var guide strings.Builder
 	switch presetName {
 	case config.PresetMinimal:
-		b.WriteString("This is a **minimal build** — move fast, ship it.\n\n")
+		guide.WriteString("This is a **minimal build** — move fast, ship it.\n\n")
 	case config.PresetLight:
-		b.WriteString("This is a **light build** — balance speed with quality.\n\n")
+		guide.WriteString("This is a **light build** — balance speed with quality.\n\n")
 	default:
-		b.WriteString("This is a **quick build** — focus on getting a working solution.\n\n")
+		guide.WriteString("This is a **quick build** — focus on getting a working solution.\n\n")
 	}
-	b.WriteString("- Keep it simple and functional\n")
-	b.WriteString("- Write clean, readable code\n")
+	guide.WriteString("- Keep it simple and functional\n")
+	guide.WriteString("- Write clean, readable code\n")
 	if cfg.IsTDDEnabled() {
-		b.WriteString("- **Write tests first** (TDD enabled)\n")
+		guide.WriteString("- **Write tests first** (TDD enabled)\n")
 	} else {
-		b.WriteString("- Include basic tests for core functionality\n")
+		guide.WriteString("- Include basic tests for core functionality\n")
 	}
-	b.WriteString("- Add a README with usage instructions\n")
-	b.WriteString("\n")
+	guide.WriteString("- Add a README with usage instructions\n")
+	b.AddSection("guidelines", "Implementation Guidelines", guide.String())
 
 	// Completion
-	b.WriteString("## Completion\n\n")
-	b.WriteString("When done:\n")
-	b.WriteString("- Run `foreman gate implementation` to mark as complete\n")
-	b.WriteString("- Ensure the build compiles/runs successfully\n")
-	b.WriteString("- All tests pass\n")
-
-	return b.String(), nil
+	var completion strings.Builder
+	completion.WriteString("When done:\n")
+	completion.WriteString("- Run `foreman gate implementation` to mark as complete\n")
+	completion.WriteString("- Ensure the build compiles/runs successfully\n")
+	completion.WriteString("- All tests pass\n")
+	b.AddSection("completion", "Completion", completion.String())
+
+	return b, nil
 }
 
 // GenerateQuickBriefAndSave creates a quick brief and saves it.
-func GenerateQuickBriefAndSave(root, task string) (string, error) {
-	brief, err := GenerateQuickBrief(root, task)
+func GenerateQuickBriefAndSave(root, dir, task string) (string, error) {
+	return GenerateQuickBriefFormatAndSave(root, dir, task, FormatMarkdown)
+}
+
+// GenerateQuickBriefFormatAndSave creates a quick brief in the given format and saves it.
+func GenerateQuickBriefFormatAndSave(root, dir, task, format string) (string, error) {
+	return GenerateQuickBriefWithOptionsAndSave(root, dir, task, Options{Format: format})
+}
+
+// GenerateQuickBriefWithOptionsAndSave creates a quick brief with the given options and saves it.
+func GenerateQuickBriefWithOptionsAndSave(root, dir, task string, opts Options) (string, error) {
+	b, err := BuildQuickWithOptions(root, dir, task, opts)
 	if err != nil {
 		return "", err
 	}
-
-	briefPath := project.BriefPath(root, "impl")
-	if err := os.WriteFile(briefPath, []byte(brief), 0644); err != nil {
-		return "", fmt.Errorf("failed to write brief: %w", err)
-	}
-
-	return brief, nil
-}
\ No newline at end of file
+	return renderAndSave(dir, "impl", opts.Format, b)
+}
@@ -0,0 +1,239 @@
+package brief
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thinkshake/foreman/internal/project"
+)
+
+// Output formats supported by Render.
+const (
+	FormatMarkdown = "markdown"  // Plain markdown (default)
+	FormatXML      = "xml"       // Sections wrapped in XML-style tags
+	FormatJSON     = "json"      // Structured sections for programmatic use
+	FormatAgentsMD = "agents-md" // AGENTS.md-style instruction file
+)
+
+// Formats lists all supported output formats.
+var Formats = []string{FormatMarkdown, FormatXML, FormatJSON, FormatAgentsMD}
+
+// Field is a single header value of a brief, such as its status.
+type Field struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Section is a titled block of markdown content with optional subsections.
+type Section struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Body     string     `json:"body,omitempty"`
+	Sections []*Section `json:"sections,omitempty"`
+}
+
+// Brief is the format-independent model every output format is rendered from.
+type Brief struct {
+	Kind     string     `json:"kind"` // "phase" or "quick"
+	Title    string     `json:"title"`
+	Phase    string     `json:"phase"`
+	Project  string     `json:"project"`
+	Meta     []Field    `json:"meta"`
+	Sections []*Section `json:"sections"`
+}
+
+// AddMeta appends a header field.
+func (b *Brief) AddMeta(key, label, value string) {
+	b.Meta = append(b.Meta, Field{Key: key, Label: label, Value: value})
+}
+
+// AddSection appends a top-level section and returns it.
+func (b *Brief) AddSection(id, title, body string) *Section {
+	s := &Section{ID: id, Title: title, Body: body}
+	b.Sections = append(b.Sections, s)
+	return s
+}
+
+// AddSection appends a subsection and returns it.
+func (s *Section) AddSection(id, title, body string) *Section {
+	sub := &Section{ID: id, Title: title, Body: body}
+	s.Sections = append(s.Sections, sub)
+	return sub
+}
+
+// IsValidFormat checks if a format name is supported.
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns where a brief in the given format is saved.
+func Path(dir, name, format string) string {
+	switch format {
+	case FormatXML:
+		return filepath.Join(project.BriefsPath(dir), filepath.FromSlash(name)+".xml")
+	case FormatJSON:
+		return filepath.Join(project.BriefsPath(dir), filepath.FromSlash(name)+".json")
+	case FormatAgentsMD:
+		return filepath.Join(project.BriefsPath(dir), filepath.FromSlash(name)+".agents.md")
+	default:
+		return project.BriefPath(dir, name)
+	}
+}
+
+// Render converts a brief model into the requested output format.
+func Render(b *Brief, format string) (string, error) {
+	switch format {
+	case FormatMarkdown, "":
+		return renderMarkdown(b), nil
+	case FormatXML:
+		return renderXML(b), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal brief: %w", err)
+		}
+		return string(data) + "\n", nil
+	case FormatAgentsMD:
+		return renderAgentsMD(b), nil
+	default:
+		return "", fmt.Errorf("unknown brief format: %s (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// renderMarkdown renders the brief as markdown with a level-1 title.
+func renderMarkdown(b *Brief) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", b.Title))
+	for _, f := range b.Meta {
+		sb.WriteString(fmt.Sprintf("**%s:** %s\n", f.Label, f.Value))
+	}
+	if len(b.Meta) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, s := range b.Sections {
+		writeMarkdownSection(&sb, s, 2)
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// writeMarkdownSection renders a section and its subsections at the given heading level.
+func writeMarkdownSection(sb *strings.Builder, s *Section, level int) {
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", level), s.Title))
+	if body := strings.TrimSpace(s.Body); body != "" {
+		sb.WriteString(body)
+		sb.WriteString("\n\n")
+	}
+	for _, sub := range s.Sections {
+		writeMarkdownSection(sb, sub, level+1)
+	}
+}
+
+// renderXML wraps each section in a tag named after its ID. Bodies stay
+// markdown; those containing markup characters are wrapped in CDATA so the
+// document stays well-formed. The tags only delimit sections for the model.
+func renderXML(b *Brief) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<brief kind=\"%s\" phase=\"%s\" project=\"%s\">\n", xmlEscape(b.Kind), xmlEscape(b.Phase), xmlEscape(b.Project)))
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n", xmlEscape(b.Title)))
+	if len(b.Meta) > 0 {
+		sb.WriteString("<meta>\n")
+		for _, f := range b.Meta {
+			name := xmlName(f.Key)
+			sb.WriteString(fmt.Sprintf("<%s>%s</%s>\n", name, xmlEscape(f.Value), name))
+		}
+		sb.WriteString("</meta>\n")
+	}
+	for _, s := range b.Sections {
+		writeXMLSection(&sb, s)
+	}
+	sb.WriteString("</brief>\n")
+	return sb.String()
+}
+
+// writeXMLSection renders a section as <id title="..."> ... </id>. IDs that
+// aren't valid tag names (such as "report-2-backend/1-auth") are kept in an
+// id attribute.
+func writeXMLSection(sb *strings.Builder, s *Section) {
+	name := xmlName(s.ID)
+	if name == s.ID {
+		sb.WriteString(fmt.Sprintf("<%s title=\"%s\">\n", name, xmlEscape(s.Title)))
+	} else {
+		sb.WriteString(fmt.Sprintf("<%s id=\"%s\" title=\"%s\">\n", name, xmlEscape(s.ID), xmlEscape(s.Title)))
+	}
+	if body := strings.TrimSpace(s.Body); body != "" {
+		sb.WriteString(xmlText(body))
+		sb.WriteString("\n")
+	}
+	for _, sub := range s.Sections {
+		writeXMLSection(sb, sub)
+	}
+	sb.WriteString(fmt.Sprintf("</%s>\n", name))
+}
+
+// xmlName turns an ID into a valid tag name: characters other than letters,
+// digits, '-', '_' and '.' become '-', and a name that doesn't start with a
+// letter or '_' gets a leading '_'.
+func xmlName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, id)
+	if first, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(first) && first != '_' {
+		name = "_" + name
+	}
+	return name
+}
+
+// xmlEscape escapes text for an element or attribute value.
+func xmlEscape(text string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(text)) // writing to a strings.Builder can't fail
+	return sb.String()
+}
+
+// xmlText returns markdown as element content: unchanged when it has no
+// markup characters, otherwise as CDATA sections.
+func xmlText(text string) string {
+	if !strings.ContainsAny(text, "<&") {
+		return text
+	}
+	// "]]>" can't appear inside CDATA, so it is split across two sections
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+// renderAgentsMD renders the brief as an AGENTS.md-style instruction file
+// addressed to the coding agent working in the repository.
+func renderAgentsMD(b *Brief) string {
+	var sb strings.Builder
+	sb.WriteString("# AGENTS.md\n\n")
+	if b.Kind == "quick" {
+		sb.WriteString(fmt.Sprintf("You are the coding agent implementing **%s**.\n", b.Project))
+	} else {
+		sb.WriteString(fmt.Sprintf("You are the coding agent implementing phase **%s** of **%s**.\n", b.Phase, b.Project))
+	}
+	sb.WriteString("Follow these instructions for all work in this repository until the task is complete.\n")
+	sb.WriteString(fmt.Sprintf("They were generated by foreman; regenerate them with `foreman brief %s --format agents-md`.\n\n", b.Phase))
+	for _, f := range b.Meta {
+		sb.WriteString(fmt.Sprintf("- **%s:** %s\n", f.Label, f.Value))
+	}
+	if len(b.Meta) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, s := range b.Sections {
+		writeMarkdownSection(&sb, s, 2)
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
@@ -0,0 +1,260 @@
+package brief
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// DefaultTreeDepth is how many directory levels the repository tree shows.
+const DefaultTreeDepth = 3
+
+// maxTreeEntries caps the tree so large repositories don't swamp the brief.
+const maxTreeEntries = 200
+
+// moduleFiles maps well-known module/manifest files to the language they indicate.
+var moduleFiles = []struct {
+	File     string
+	Language string
+}{
+	{"go.mod", "Go"},
+	{"package.json", "JavaScript/TypeScript"},
+	{"tsconfig.json", "TypeScript"},
+	{"Cargo.toml", "Rust"},
+	{"pyproject.toml", "Python"},
+	{"requirements.txt", "Python"},
+	{"pom.xml", "Java"},
+	{"build.gradle", "Java/Kotlin"},
+	{"Gemfile", "Ruby"},
+	{"composer.json", "PHP"},
+}
+
+// repositoryContext renders the Repository Context section for the project root.
+// previous is the most recently completed preceding phase (nil if there is none).
+func repositoryContext(root string, depth int, previous *state.Phase) string {
+	var b strings.Builder
+
+	// Language and module files
+	var found, languages []string
+	seen := make(map[string]bool)
+	for _, m := range moduleFiles {
+		if _, err := os.Stat(filepath.Join(root, m.File)); err == nil {
+			found = append(found, m.File)
+			if !seen[m.Language] {
+				languages = append(languages, m.Language)
+				seen[m.Language] = true
+			}
+		}
+	}
+	if len(languages) > 0 {
+		b.WriteString(fmt.Sprintf("**Language:** %s\n", strings.Join(languages, ", ")))
+		b.WriteString(fmt.Sprintf("**Module files:** %s\n", strings.Join(found, ", ")))
+	} else {
+		b.WriteString("**Language:** _not detected_\n")
+	}
+
+	// Git branch and HEAD
+	isRepo := git.IsRepo(root)
+	if isRepo {
+		branch, err := git.CurrentBranch(root)
+		if err != nil {
+			branch = "_unknown_"
+		}
+		b.WriteString(fmt.Sprintf("**Branch:** %s\n", branch))
+		if head, err := git.Head(root); err == nil {
+			b.WriteString(fmt.Sprintf("**HEAD:** %s\n", git.ShortHash(head)))
+		} else {
+			b.WriteString("**HEAD:** _no commits yet_\n")
+		}
+	} else {
+		b.WriteString("**Git:** _not a git repository_\n")
+	}
+
+	// File tree
+	b.WriteString(fmt.Sprintf("\n**File tree** (depth %d):\n\n```\n", depth))
+	b.WriteString(fileTree(root, depth, isRepo))
+	b.WriteString("```\n")
+
+	// Changes since the previous phase completed
+	if isRepo {
+		b.WriteString("\n**Changes since previous phase:**\n\n")
+		b.WriteString(changesSince(root, previous))
+	}
+
+	return b.String()
+}
+
+// changesSince renders the diffstat between the end of the previous phase and the work tree.
+// The phase's recorded end commit is used when available, otherwise the last
+// commit before it was completed.
+func changesSince(root string, previous *state.Phase) string {
+	if previous == nil {
+		return "_No previous phase has been completed yet._\n"
+	}
+
+	commit := previous.EndCommit
+	if commit == "" {
+		var err error
+		commit, err = git.CommitBefore(root, *previous.CompletedAt)
+		if err != nil {
+			return fmt.Sprintf("_No commit found before %s._\n", previous.CompletedAt.Format("2006-01-02 15:04"))
+		}
+	}
+
+	stat, err := git.DiffStat(root, commit)
+	if err != nil {
+		return fmt.Sprintf("_Could not compute diffstat: %v_\n", err)
+	}
+	if stat == "" {
+		return fmt.Sprintf("_No changes since %s (%s)._\n", git.ShortHash(commit), previous.Name)
+	}
+
+	return fmt.Sprintf("Since %s (%s completed):\n\n```\n%s\n```\n", git.ShortHash(commit), previous.Name, stat)
+}
+
+// lastCompleted returns the most recently completed phase, or nil if none is done.
+func lastCompleted(phases []state.Phase) *state.Phase {
+	var latest *state.Phase
+	for i := range phases {
+		phase := &phases[i]
+		if phase.Status != "done" || phase.CompletedAt == nil {
+			continue
+		}
+		if latest == nil || phase.CompletedAt.After(*latest.CompletedAt) {
+			latest = phase
+		}
+	}
+	return latest
+}
+
+// fileTree renders a depth-limited tree of the repository. Inside a git
+// repository the file list comes from git, so .gitignore is honored; otherwise
+// the root .gitignore is applied to a directory walk.
+func fileTree(root string, depth int, isRepo bool) string {
+	var files []string
+	var err error
+	if isRepo {
+		files, err = git.ListFiles(root)
+	}
+	if !isRepo || err != nil {
+		files = walkFiles(root, readGitignore(root))
+	}
+
+	// Collect unique paths up to the depth limit
+	entries := make(map[string]bool)
+	for _, f := range files {
+		f = filepath.ToSlash(f)
+		if f == "" || strings.HasPrefix(f, project.ForemanDir+"/") {
+			continue
+		}
+		parts := strings.Split(f, "/")
+		for i := 1; i <= len(parts) && i <= depth; i++ {
+			path := strings.Join(parts[:i], "/")
+			if i < len(parts) {
+				path += "/"
+			}
+			entries[path] = true
+		}
+	}
+
+	paths := make([]string, 0, len(entries))
+	for p := range entries {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for i, p := range paths {
+		if i == maxTreeEntries {
+			b.WriteString(fmt.Sprintf("… (%d more entries)\n", len(paths)-maxTreeEntries))
+			break
+		}
+		trimmed := strings.TrimSuffix(p, "/")
+		level := strings.Count(trimmed, "/")
+		name := filepath.Base(trimmed)
+		if strings.HasSuffix(p, "/") {
+			name += "/"
+		}
+		b.WriteString(strings.Repeat("  ", level) + name + "\n")
+	}
+	if len(paths) == 0 {
+		b.WriteString("(empty)\n")
+	}
+
+	return b.String()
+}
+
+// readGitignore returns the patterns of the root .gitignore.
+func readGitignore(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// isIgnored checks a slash-separated relative path against gitignore patterns.
+func isIgnored(rel string, isDir bool, patterns []string) bool {
+	for _, pattern := range patterns {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+
+		if strings.Contains(pattern, "/") {
+			// Anchored pattern: match against the full relative path
+			if ok, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), rel); ok {
+				return true
+			}
+			continue
+		}
+
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walkFiles lists files under root, skipping .git and ignored paths.
+func walkFiles(root string, patterns []string) []string {
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" || isIgnored(rel, true, patterns) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isIgnored(rel, false, patterns) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files
+}
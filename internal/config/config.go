@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -33,6 +34,51 @@ type Testing struct {
 	MinCover  int    `yaml:"min_cover,omitempty"` // Minimum coverage percentage (for coverage style)
 }
 
+// Git defines opt-in git integration for the local repository.
+type Git struct {
+	Branches     bool   `yaml:"branches,omitempty"`      // Check out a branch per phase when it goes in-progress
+	BranchPrefix string `yaml:"branch_prefix,omitempty"` // Phase branch prefix (default "phase/")
+	Tags         bool   `yaml:"tags,omitempty"`          // Create an annotated tag when a gate is approved
+	TagPrefix    string `yaml:"tag_prefix,omitempty"`    // Gate tag prefix (default "foreman/")
+	Commits      bool   `yaml:"commits,omitempty"`       // Commit .foreman/ after each state change
+}
+
+// Default git naming prefixes.
+const (
+	DefaultBranchPrefix = "phase/"
+	DefaultTagPrefix    = "foreman/"
+)
+
+// PhaseBranch returns the branch name for a phase of track. Nested phase
+// names are flattened ("2-backend/1-auth" → "2-backend--1-auth") so they don't
+// clash with the parent phase's branch.
+func (g *Git) PhaseBranch(track, phase string) string {
+	prefix := g.BranchPrefix
+	if prefix == "" {
+		prefix = DefaultBranchPrefix
+	}
+	return prefix + trackNamespace(track) + strings.ReplaceAll(phase, "/", "--")
+}
+
+// GateTag returns the tag name for an approved gate of track.
+func (g *Git) GateTag(track, stage string) string {
+	prefix := g.TagPrefix
+	if prefix == "" {
+		prefix = DefaultTagPrefix
+	}
+	return prefix + trackNamespace(track) + stage + "-approved"
+}
+
+// trackNamespace returns what follows the prefix in the branch and tag names
+// of a named track ("billing/"), so that the tracks of a repository don't
+// share names. The main track has none.
+func trackNamespace(track string) string {
+	if track == "" || track == MainTrack {
+		return ""
+	}
+	return track + "/"
+}
+
 // Config represents the config.yaml schema.
 type Config struct {
 	Name        string    `yaml:"name"`
@@ -44,6 +90,9 @@ type Config struct {
 	AutoAdvance int       `yaml:"auto_advance,omitempty"` // confidence threshold for auto-advance (0-100)
 	Testing     *Testing  `yaml:"testing,omitempty"`      // v2.1: testing configuration
 	Workflow    []string  `yaml:"workflow,omitempty"`     // v2.1: custom workflow stages (power users)
+	Git         *Git      `yaml:"git,omitempty"`          // opt-in git integration
+
+	DefaultTrack string `yaml:"default_track,omitempty"` // track used without --track (main config only)
 }
 
 // Reviewers defines gate reviewer configuration.
@@ -73,43 +122,46 @@ func (r *Reviewers) SetReviewer(stage, reviewer string) {
 	}
 }
 
-// ConfigPath returns the path to config.yaml.
-func ConfigPath(root string) string {
-	return filepath.Join(root, ".foreman", "config.yaml")
+// ConfigPath returns the path to config.yaml in a track directory (see TrackDir).
+func ConfigPath(dir string) string {
+	return filepath.Join(dir, "config.yaml")
 }
 
-// Load reads and parses config.yaml from the given root.
-func Load(root string) (*Config, error) {
-	path := ConfigPath(root)
-	data, err := os.ReadFile(path)
+// Load reads the effective configuration of the track in dir: defaults,
+// then the global config, the track's config.yaml, FOREMAN_* environment
+// variables and -c flags, each overriding the values of the ones before.
+func Load(dir string) (*Config, error) {
+	c, _, err := LoadWithOrigins(dir)
+	return c, err
+}
+
+// Save writes the config back to config.yaml. An existing file is updated in
+// place, so its comments, key order and unknown keys survive.
+func Save(dir string, c *Config) error {
+	data, err := Marshal(dir, c)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config.yaml: %w", err)
+		return err
 	}
-	
-	var c Config
-	if err := yaml.Unmarshal(data, &c); err != nil {
-		return nil, fmt.Errorf("failed to parse config.yaml: %w", err)
-	}
-	
-	// Ensure reviewers has defaults
-	if c.Reviewers.Default == "" {
-		c.Reviewers.Default = "auto"
-	}
-	if c.Reviewers.Overrides == nil {
-		c.Reviewers.Overrides = make(map[string]string)
-	}
-	
-	return &c, nil
+	return os.WriteFile(ConfigPath(dir), data, 0644)
 }
 
-// Save writes the config back to config.yaml.
-func Save(root string, c *Config) error {
-	path := ConfigPath(root)
-	data, err := yaml.Marshal(c)
+// Marshal returns the config.yaml content Save would write for the config.
+func Marshal(dir string, c *Config) ([]byte, error) {
+	existing, err := os.ReadFile(ConfigPath(dir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config.yaml: %w", err)
+	}
+
+	var data []byte
+	if len(existing) > 0 {
+		data, err = updateDocument(existing, c)
+	} else {
+		data, err = yaml.Marshal(c)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to marshal config.yaml: %w", err)
+		return nil, fmt.Errorf("failed to marshal config.yaml: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	return data, nil
 }
 
 // NewDefault creates a default config.
@@ -130,33 +182,14 @@ func NewDefault(name string) *Config {
 	}
 }
 
-// NewWithPreset creates a config with a specific preset.
+// NewWithPreset creates a config with a built-in preset. Other names are
+// recorded as-is without settings; use FindPreset to resolve user-defined presets.
 func NewWithPreset(name, preset string) *Config {
-	cfg := NewDefault(name)
-	cfg.Preset = NormalizePreset(preset)
-
-	switch cfg.Preset {
-	case PresetMinimal:
-		// Minimal: scripts/hotfixes - no gates, straight to implementation
-		cfg.AutoAdvance = 100 // Auto-advance everything
-		cfg.Reviewers.Default = "auto"
-		cfg.Workflow = []string{"requirements", "implementation"}
-	case PresetLight:
-		// Light: small tools - requirements gate only, no design phase
-		cfg.AutoAdvance = 70 // Auto-advance at 70% confidence
-		cfg.Reviewers.Default = "auto"
-		cfg.Workflow = []string{"requirements", "implementation"}
-	case PresetFull:
-		// Full: products - all gates, human review for key stages
-		cfg.AutoAdvance = 0 // No auto-advance
-		cfg.Reviewers.Default = "auto"
-		cfg.Reviewers.Overrides = map[string]string{
-			"requirements": "human",
-			"design":       "human",
-		}
-		cfg.Workflow = []string{"requirements", "design", "phases", "implementation"}
+	if p := BuiltinPreset(preset); p != nil {
+		return NewFromPreset(name, p)
 	}
-
+	cfg := NewDefault(name)
+	cfg.Preset = preset
 	return cfg
 }
 
@@ -241,6 +274,21 @@ func (c *Config) IsTDDEnabled() bool {
 	return c.Testing != nil && c.Testing.Style == TestingStyleTDD
 }
 
+// GitBranchesEnabled returns true if phases get their own branches.
+func (c *Config) GitBranchesEnabled() bool {
+	return c.Git != nil && c.Git.Branches
+}
+
+// GitTagsEnabled returns true if gate approvals are tagged.
+func (c *Config) GitTagsEnabled() bool {
+	return c.Git != nil && c.Git.Tags
+}
+
+// GitCommitsEnabled returns true if state changes are committed automatically.
+func (c *Config) GitCommitsEnabled() bool {
+	return c.Git != nil && c.Git.Commits
+}
+
 // ValidateWorkflow checks if a custom workflow is valid.
 func ValidateWorkflow(workflow []string) error {
 	if len(workflow) == 0 {
@@ -273,4 +321,20 @@ func ValidateWorkflow(workflow []string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Validate checks the config's workflow, reviewers, auto-advance threshold
+// and testing settings.
+func (c *Config) Validate() error {
+	if len(c.Workflow) > 0 {
+		if err := ValidateWorkflow(c.Workflow); err != nil {
+			return err
+		}
+	}
+	if c.DefaultTrack != "" && c.DefaultTrack != MainTrack {
+		if err := ValidateTrackName(c.DefaultTrack); err != nil {
+			return fmt.Errorf("invalid default_track: %w", err)
+		}
+	}
+	return validateSettings(c.GetWorkflow(), c.Reviewers, c.AutoAdvance, c.Testing)
+}
@@ -3,10 +3,29 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
+// TestMain keeps the user's global config and FOREMAN_* variables out of the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "foreman-user-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, EnvPrefix) {
+			os.Unsetenv(key)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestNewDefault(t *testing.T) {
 	cfg := NewDefault("test-project")
 	
@@ -263,12 +282,12 @@ func TestLoadSave(t *testing.T) {
 		},
 	}
 	
-	if err := Save(tempDir, cfg); err != nil {
+	if err := Save(foremanDir, cfg); err != nil {
 		t.Fatal(err)
 	}
 	
 	// Load config back
-	loaded, err := Load(tempDir)
+	loaded, err := Load(foremanDir)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -293,4 +312,121 @@ func TestLoadSave(t *testing.T) {
 	if loaded.Reviewers.GetReviewer("requirements") != "auto" {
 		t.Errorf("expected requirements reviewer 'auto', got '%s'", loaded.Reviewers.GetReviewer("requirements"))
 	}
-}
\ No newline at end of file
+}
+
+func TestGitSettings(t *testing.T) {
+	cfg := NewDefault("test")
+	if cfg.GitBranchesEnabled() || cfg.GitTagsEnabled() || cfg.GitCommitsEnabled() {
+		t.Error("git integration should be off by default")
+	}
+
+	cfg.Git = &Git{Branches: true, Tags: true}
+	if !cfg.GitBranchesEnabled() || !cfg.GitTagsEnabled() {
+		t.Error("expected branches and tags to be enabled")
+	}
+	if cfg.GitCommitsEnabled() {
+		t.Error("expected commits to stay disabled")
+	}
+
+	if got := cfg.Git.PhaseBranch(MainTrack, "2-backend"); got != "phase/2-backend" {
+		t.Errorf("expected default branch phase/2-backend, got %s", got)
+	}
+	if got := cfg.Git.PhaseBranch(MainTrack, "2-backend/1-auth"); got != "phase/2-backend--1-auth" {
+		t.Errorf("expected nested phase branch phase/2-backend--1-auth, got %s", got)
+	}
+	if got := cfg.Git.GateTag(MainTrack, "design"); got != "foreman/design-approved" {
+		t.Errorf("expected default tag foreman/design-approved, got %s", got)
+	}
+
+	cfg.Git.BranchPrefix = "work/"
+	cfg.Git.TagPrefix = "gates/"
+	if got := cfg.Git.PhaseBranch(MainTrack, "2-backend"); got != "work/2-backend" {
+		t.Errorf("expected custom branch work/2-backend, got %s", got)
+	}
+	if got := cfg.Git.GateTag(MainTrack, "design"); got != "gates/design-approved" {
+		t.Errorf("expected custom tag gates/design-approved, got %s", got)
+	}
+
+	// Named tracks get their own namespace after the prefix
+	if got := cfg.Git.PhaseBranch("billing", "2-backend"); got != "work/billing/2-backend" {
+		t.Errorf("expected track branch work/billing/2-backend, got %s", got)
+	}
+	if got := cfg.Git.GateTag("billing", "design"); got != "gates/billing/design-approved" {
+		t.Errorf("expected track tag gates/billing/design-approved, got %s", got)
+	}
+}
+
+func TestSavePreservesDocument(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), ".foreman")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := `# Settings for reviewers
+
+name: demo # short name
+tech_stack: [go, postgres]
+created: 2026-02-19T00:00:00Z
+
+# Who reviews which gate
+reviewers:
+  default: auto
+  overrides:
+    design: human # architecture needs eyes
+    phases: human
+
+# Not used by foreman
+owner: platform-team
+testing:
+  style: tdd # we do TDD
+`
+	if err := os.WriteFile(ConfigPath(dir), []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving an unchanged config leaves the file as it was
+	cfg, err := LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(ConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected unchanged file, got:\n%s", data)
+	}
+
+	if err := cfg.Set("testing.min_cover", "80"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Unset("reviewers.overrides.phases"); err != nil {
+		t.Fatal(err)
+	}
+	cfg.TechStack = append(cfg.TechStack, "redis")
+	if err := Save(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(ConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Replace(original, "tech_stack: [go, postgres]", "tech_stack: [go, postgres, redis]", 1)
+	want = strings.Replace(want, "    phases: human\n", "", 1)
+	want += "  min_cover: 80\n"
+	if string(data) != want {
+		t.Errorf("expected only the changed values to be rewritten, got:\n%s\nwant:\n%s", data, want)
+	}
+
+	loaded, err := LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Testing.MinCover != 80 || loaded.Reviewers.GetReviewer("phases") != "auto" || len(loaded.TechStack) != 3 {
+		t.Errorf("unexpected config after save: %+v", loaded)
+	}
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// updateDocument writes the config's values into existing config.yaml
+// content. Only values that changed are rewritten: comments, key order, blank
+// lines, indentation and keys foreman doesn't know are kept. Content that
+// isn't a YAML mapping is replaced.
+func updateDocument(data []byte, c *Config) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return yaml.Marshal(c)
+	}
+
+	var updated yaml.Node
+	if err := updated.Encode(c); err != nil {
+		return nil, err
+	}
+
+	root := doc.Content[0]
+	blanks := blankLinePaths(root, strings.Split(string(data), "\n"), "")
+	mergeMapping(root, &updated, "")
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return restoreBlankLines(buf.Bytes(), blanks), nil
+}
+
+// mergeMapping updates a mapping node from the file with the config's values.
+// Settings the config no longer has are removed, unless they are empty or
+// unknown to foreman; new settings are appended, unless they are empty.
+func mergeMapping(old, updated *yaml.Node, prefix string) {
+	if len(old.Content) == 0 {
+		old.Style = updated.Style
+	}
+
+	content := old.Content[:0]
+	for i := 0; i+1 < len(old.Content); i += 2 {
+		key, value := old.Content[i], old.Content[i+1]
+		path := prefix + key.Value
+		if mappingValue(updated, key.Value) == nil && knownPath(path) && !isEmptyNode(value) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	old.Content = content
+
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key, value := updated.Content[i], updated.Content[i+1]
+		if existing := mappingValue(old, key.Value); existing != nil {
+			mergeValue(existing, value, prefix+key.Value)
+		} else if !isEmptyNode(value) {
+			old.Content = append(old.Content, key, value)
+		}
+	}
+}
+
+// mergeValue updates a value node from the file, keeping its comments and,
+// where it still fits, its style.
+func mergeValue(old, updated *yaml.Node, path string) {
+	if old.Kind == yaml.MappingNode && updated.Kind == yaml.MappingNode {
+		mergeMapping(old, updated, path+".")
+		return
+	}
+	if sameValue(old, updated) {
+		return
+	}
+
+	keepStyle := old.Kind == updated.Kind &&
+		(old.Kind == yaml.SequenceNode && len(old.Content) > 0 || old.Kind == yaml.ScalarNode && updated.Tag == "!!str")
+	if !keepStyle {
+		old.Style = updated.Style
+	}
+	old.Kind = updated.Kind
+	old.Tag = updated.Tag
+	old.Value = updated.Value
+	old.Content = updated.Content
+}
+
+// sameValue reports whether two scalar or list nodes hold the same value.
+func sameValue(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		if a.Value == b.Value {
+			return true
+		}
+		var ta, tb time.Time
+		return b.Tag == "!!timestamp" && a.Decode(&ta) == nil && b.Decode(&tb) == nil && ta.Equal(tb)
+	case yaml.SequenceNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !sameValue(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// mappingValue returns the value for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// knownPath reports whether a dot path is a setting or section of the schema.
+func knownPath(path string) bool {
+	_, err := LookupField(path)
+	return err == nil || isSection(path)
+}
+
+// isEmptyNode reports whether a node holds an empty value: null, "", [] or {}.
+func isEmptyNode(node *yaml.Node) bool {
+	if node.Kind == yaml.AliasNode || len(node.Content) > 0 {
+		return false
+	}
+	return node.Kind != yaml.ScalarNode || node.Value == "" || node.Tag == "!!null"
+}
+
+// detectIndent returns the indentation of the content's first nested line, or 4.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return len(line) - len(trimmed)
+	}
+	return 4
+}
+
+// keyStart returns the first line of a mapping key, including its head comment.
+func keyStart(key *yaml.Node) int {
+	if key.HeadComment == "" {
+		return key.Line
+	}
+	return key.Line - strings.Count(key.HeadComment, "\n") - 1
+}
+
+// blankLinePaths lists the dot paths of the keys preceded by a blank line.
+func blankLinePaths(node *yaml.Node, lines []string, prefix string) map[string]bool {
+	paths := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := prefix + key.Value
+		if start := keyStart(key); start >= 2 && start-2 < len(lines) && strings.TrimSpace(lines[start-2]) == "" {
+			paths[path] = true
+		}
+		if value.Kind == yaml.MappingNode {
+			for p := range blankLinePaths(value, lines, path+".") {
+				paths[p] = true
+			}
+		}
+	}
+	return paths
+}
+
+// restoreBlankLines puts back the blank lines before the given keys, which
+// the YAML encoder drops.
+func restoreBlankLines(data []byte, paths map[string]bool) []byte {
+	if len(paths) == 0 {
+		return data
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return data
+	}
+
+	var starts []int
+	var collect func(node *yaml.Node, prefix string)
+	collect = func(node *yaml.Node, prefix string) {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := prefix + key.Value
+			if paths[path] {
+				starts = append(starts, keyStart(key))
+			}
+			if value.Kind == yaml.MappingNode {
+				collect(value, path+".")
+			}
+		}
+	}
+	collect(doc.Content[0], "")
+
+	lines := strings.Split(string(data), "\n")
+	sort.Sort(sort.Reverse(sort.IntSlice(starts)))
+	for _, start := range starts {
+		if start < 2 || start > len(lines) || strings.TrimSpace(lines[start-2]) == "" {
+			continue
+		}
+		lines = append(lines[:start-1], append([]string{""}, lines[start-1:]...)...)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,329 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MapKey stands for the key of a map setting in a field path, e.g.
+// "reviewers.overrides.<key>".
+const MapKey = "<key>"
+
+// Field is a config.yaml setting addressable by a dot path such as
+// "testing.framework" or "reviewers.overrides.design".
+type Field struct {
+	Path  string       // dot path; map settings end in ".<key>"
+	Type  reflect.Type // type of the value (the element type for map settings)
+	IsMap bool         // one entry of a map setting
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Fields lists every setting of the Config schema, in schema order.
+func Fields() []Field {
+	return collectFields(reflect.TypeOf(Config{}), "")
+}
+
+func collectFields(t reflect.Type, prefix string) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct && ft != timeType:
+			fields = append(fields, collectFields(ft, path+".")...)
+		case ft.Kind() == reflect.Map:
+			fields = append(fields, Field{Path: path + "." + MapKey, Type: ft.Elem(), IsMap: true})
+		default:
+			fields = append(fields, Field{Path: path, Type: ft})
+		}
+	}
+	return fields
+}
+
+// LookupField finds the field for a dot path. Map entries match their map
+// setting: "reviewers.overrides.design" yields "reviewers.overrides.<key>".
+func LookupField(path string) (Field, error) {
+	for _, f := range Fields() {
+		if f.Path == path {
+			return f, nil
+		}
+		if prefix, ok := strings.CutSuffix(f.Path, MapKey); ok {
+			if key := strings.TrimPrefix(path, prefix); strings.HasPrefix(path, prefix) && key != "" && !strings.Contains(key, ".") {
+				return f, nil
+			}
+		}
+	}
+	return Field{}, fmt.Errorf("unknown configuration key: %s", path)
+}
+
+// EnvName returns the environment variable that sets the field, e.g.
+// FOREMAN_TESTING_FRAMEWORK. Map settings take the key as a suffix
+// (FOREMAN_REVIEWERS_OVERRIDES_DESIGN).
+func (f Field) EnvName() string {
+	path := strings.TrimSuffix(f.Path, "."+MapKey)
+	name := EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+	if f.IsMap {
+		name += "_"
+	}
+	return name
+}
+
+// Node parses a command-line or environment value into a YAML node of the
+// field's type. Lists are comma-separated.
+func (f Field) Node(value string) (*yaml.Node, error) {
+	switch {
+	case f.Type.Kind() == reflect.Slice:
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		if len(list.Content) == 0 {
+			list.Style = yaml.FlowStyle
+		}
+		return list, nil
+	case f.Type == timeType:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 time, got %q", f.Path, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: value}, nil
+	case f.Type.Kind() == reflect.Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("%s must be a number, got %q", f.Path, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}, nil
+	case f.Type.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", f.Path, value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+}
+
+// setNodePath sets the value at a dot path in a YAML mapping node, creating
+// intermediate mappings as needed.
+func setNodePath(doc *yaml.Node, path string, value *yaml.Node) {
+	node := doc
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if i == len(keys)-1 {
+			if child != nil {
+				*child = *value
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+			}
+			return
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		} else if child.Kind != yaml.MappingNode {
+			*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		node = child
+	}
+}
+
+// leafPaths lists the dot paths of the values in a YAML mapping node, in
+// document order, with the nodes holding them. Lists are single values.
+func leafPaths(node *yaml.Node, prefix string) ([]string, []*yaml.Node) {
+	var paths []string
+	var values []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path := prefix + node.Content[i].Value
+		value := node.Content[i+1]
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			p, v := leafPaths(value, path+".")
+			paths = append(paths, p...)
+			values = append(values, v...)
+			continue
+		}
+		paths = append(paths, path)
+		values = append(values, value)
+	}
+	return paths, values
+}
+
+// Value is one effective setting, formatted for display.
+type Value struct {
+	Path  string
+	Value string // YAML flow syntax, e.g. "go test" or "[go, cobra]"
+}
+
+// Values flattens a config into its settings, in schema order. Lists and
+// empty maps are single values.
+func Values(c *Config) ([]Value, error) {
+	var doc yaml.Node
+	if err := doc.Encode(c); err != nil {
+		return nil, err
+	}
+
+	paths, nodes := leafPaths(&doc, "")
+	values := make([]Value, len(paths))
+	for i, node := range nodes {
+		node.Style |= yaml.FlowStyle
+		data, err := yaml.Marshal(node)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = Value{Path: paths[i], Value: strings.TrimSpace(string(data))}
+	}
+	return values, nil
+}
+
+// isSection reports whether path names a group of settings, e.g. "testing".
+func isSection(path string) bool {
+	for _, f := range Fields() {
+		if strings.HasPrefix(f.Path, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value at a dot path in YAML syntax: scalars and lists on
+// one line, sections as a block. Unset settings yield "".
+func (c *Config) Get(path string) (string, error) {
+	if _, err := LookupField(path); err != nil && !isSection(path) {
+		return "", err
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(c); err != nil {
+		return "", err
+	}
+	node := findNodePath(&doc, path)
+	if node == nil {
+		return "", nil
+	}
+	if node.Kind != yaml.MappingNode || len(node.Content) == 0 {
+		node.Style |= yaml.FlowStyle
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// Set parses value for the setting at a dot path and stores it. The config
+// is left unchanged if the result is invalid.
+func (c *Config) Set(path, value string) error {
+	field, err := LookupField(path)
+	if err != nil {
+		return err
+	}
+	node, err := field.Node(value)
+	if err != nil {
+		return err
+	}
+	return c.update(func(doc *yaml.Node) {
+		setNodePath(doc, path, node)
+	})
+}
+
+// Unset removes the setting, or section of settings, at a dot path, so it
+// takes its default value. The config is left unchanged if the result is invalid.
+func (c *Config) Unset(path string) error {
+	if _, err := LookupField(path); err != nil && !isSection(path) {
+		return err
+	}
+	return c.update(func(doc *yaml.Node) {
+		deleteNodePath(doc, path)
+	})
+}
+
+// update edits the config through its YAML form and validates the result.
+func (c *Config) update(edit func(doc *yaml.Node)) error {
+	var doc yaml.Node
+	if err := doc.Encode(c); err != nil {
+		return err
+	}
+	edit(&doc)
+
+	var updated Config
+	if err := doc.Decode(&updated); err != nil {
+		return err
+	}
+	applyDefaults(&updated)
+
+	// Overrides matching the default reviewer are redundant
+	for stage, reviewer := range updated.Reviewers.Overrides {
+		updated.Reviewers.SetReviewer(stage, reviewer)
+	}
+
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	*c = updated
+	return nil
+}
+
+// findNodePath returns the node at a dot path in a YAML mapping node, or nil.
+func findNodePath(doc *yaml.Node, path string) *yaml.Node {
+	node := doc
+	for _, key := range strings.Split(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
+
+// deleteNodePath removes the entry at a dot path from a YAML mapping node.
+func deleteNodePath(doc *yaml.Node, path string) {
+	parentPath, key := "", path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parentPath, key = path[:i], path[i+1:]
+	}
+	parent := doc
+	if parentPath != "" {
+		parent = findNodePath(doc, parentPath)
+	}
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	for j := 0; j+1 < len(parent.Content); j += 2 {
+		if parent.Content[j].Value == key {
+			parent.Content = append(parent.Content[:j], parent.Content[j+2:]...)
+			return
+		}
+	}
+}
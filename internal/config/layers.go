@@ -0,0 +1,268 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, in increasing precedence. Each layer overrides the
+// values set by the layers before it; unset values fall through.
+const (
+	LayerDefault = "default" // built-in defaults
+	LayerGlobal  = "global"  // ~/.config/foreman/config.yaml
+	LayerProject = "project" // .foreman/config.yaml
+	LayerEnv     = "env"     // FOREMAN_* environment variables
+	LayerFlag    = "flag"    // -c key=value on the command line
+)
+
+// EnvPrefix starts the environment variables that set configuration values,
+// e.g. FOREMAN_TESTING_FRAMEWORK for testing.framework.
+const EnvPrefix = "FOREMAN_"
+
+// Origin tells where an effective configuration value came from.
+type Origin struct {
+	Layer  string
+	Source string // file, environment variable or flag that set the value
+}
+
+// String formats the origin for display, e.g. "env (FOREMAN_TESTING_FRAMEWORK)".
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer
+	}
+	return fmt.Sprintf("%s (%s)", o.Layer, o.Source)
+}
+
+// layer is one source of configuration values.
+type layer struct {
+	name    string
+	file    string            // config file of the global and project layers
+	node    *yaml.Node        // mapping node with the layer's settings
+	sources map[string]string // dot path → file, variable or flag that set it
+}
+
+// flagOverrides holds the -c key=value settings of the current command.
+var flagOverrides *layer
+
+// GlobalConfigPath returns the path of the user-level config.yaml, or "" if
+// the home directory is unknown.
+func GlobalConfigPath() string {
+	dir := UserDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.yaml")
+}
+
+// SetOverrides sets the flag layer from "key=value" pairs. The values apply
+// to every later Load.
+func SetOverrides(pairs []string) error {
+	if len(pairs) == 0 {
+		flagOverrides = nil
+		return nil
+	}
+
+	l := &layer{name: LayerFlag, node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, sources: make(map[string]string)}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid override %q: expected key=value", pair)
+		}
+		key = strings.TrimSpace(key)
+		if err := l.set(key, value, "-c "+key); err != nil {
+			return err
+		}
+	}
+	flagOverrides = l
+	return nil
+}
+
+// set parses value for the field at path and records its source.
+func (l *layer) set(path, value, source string) error {
+	field, err := LookupField(path)
+	if err != nil {
+		return err
+	}
+	node, err := field.Node(value)
+	if err != nil {
+		return err
+	}
+	setNodePath(l.node, path, node)
+	l.sources[path] = source
+	return nil
+}
+
+// fileLayer reads a config file into a layer. A missing optional file yields nil.
+func fileLayer(name, path string, optional bool) (*layer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	l := &layer{name: name, file: path, node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, sources: make(map[string]string)}
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		l.node = doc.Content[0]
+	}
+	pruneEmpty(l.node)
+
+	paths, _ := leafPaths(l.node, "")
+	for _, p := range paths {
+		l.sources[p] = path
+	}
+	return l, nil
+}
+
+// pruneEmpty drops empty values (null, "", [] and {}) from a mapping node,
+// so e.g. the "tech_stack: []" written by init doesn't hide the global tech stack.
+func pruneEmpty(node *yaml.Node) {
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			pruneEmpty(value)
+		}
+		if isEmptyNode(value) {
+			continue
+		}
+		content = append(content, node.Content[i], value)
+	}
+	node.Content = content
+}
+
+// envLayer collects the FOREMAN_* variables that name a configuration field.
+// Other FOREMAN_* variables are ignored.
+func envLayer() (*layer, error) {
+	l := &layer{name: LayerEnv, node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, sources: make(map[string]string)}
+	fields := Fields()
+
+	env := os.Environ()
+	sort.Strings(env)
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		for _, f := range fields {
+			path := ""
+			if f.IsMap {
+				if key, ok := strings.CutPrefix(name, f.EnvName()); ok && key != "" {
+					path = strings.TrimSuffix(f.Path, MapKey) + strings.ToLower(key)
+				}
+			} else if name == f.EnvName() {
+				path = f.Path
+			}
+			if path == "" {
+				continue
+			}
+			if err := l.set(path, value, name); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			break
+		}
+	}
+
+	if len(l.sources) == 0 {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// readLayers reads the global, project, env and flag layers in precedence order.
+func readLayers(dir string) ([]*layer, error) {
+	var layers []*layer
+
+	if path := GlobalConfigPath(); path != "" {
+		global, err := fileLayer(LayerGlobal, path, true)
+		if err != nil {
+			return nil, fmt.Errorf("global config %s: %w", path, err)
+		}
+		if global != nil {
+			layers = append(layers, global)
+		}
+	}
+
+	project, err := fileLayer(LayerProject, ConfigPath(dir), false)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, project)
+
+	env, err := envLayer()
+	if err != nil {
+		return nil, err
+	}
+	if env != nil {
+		layers = append(layers, env)
+	}
+
+	if flagOverrides != nil {
+		layers = append(layers, flagOverrides)
+	}
+	return layers, nil
+}
+
+// LoadWithOrigins loads the effective configuration and tells, for each value
+// set by a layer, where it came from. Values missing from the map are defaults.
+func LoadWithOrigins(dir string) (*Config, map[string]Origin, error) {
+	layers, err := readLayers(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c Config
+	origins := make(map[string]Origin)
+	for _, l := range layers {
+		if err := l.node.Decode(&c); err != nil {
+			source := l.name
+			if l.file != "" {
+				source = filepath.Base(l.file)
+			}
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", source, err)
+		}
+		for path, source := range l.sources {
+			origins[path] = Origin{Layer: l.name, Source: source}
+		}
+	}
+
+	applyDefaults(&c)
+	return &c, origins, nil
+}
+
+// LoadProject reads only the track's config.yaml, without the global, env and
+// flag layers. Commands that modify and save the project config use it so
+// values from other layers are not written into the project.
+func LoadProject(dir string) (*Config, error) {
+	l, err := fileLayer(LayerProject, ConfigPath(dir), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := l.node.Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to parse config.yaml: %w", err)
+	}
+	applyDefaults(&c)
+	return &c, nil
+}
+
+// applyDefaults fills in the values every config needs.
+func applyDefaults(c *Config) {
+	if c.Reviewers.Default == "" {
+		c.Reviewers.Default = "auto"
+	}
+	if c.Reviewers.Overrides == nil {
+		c.Reviewers.Overrides = make(map[string]string)
+	}
+}
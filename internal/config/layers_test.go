@@ -0,0 +1,226 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// layeredProject creates a project with the given config.yaml and an
+// isolated user config directory holding the given global config, and
+// returns the project root.
+func layeredProject(t *testing.T, project, global string) string {
+	t.Helper()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if global != "" {
+		writePreset(t, filepath.Join(xdg, "foreman"), "config.yaml", global)
+	}
+	root := t.TempDir()
+	writePreset(t, filepath.Join(root, ".foreman"), "config.yaml", project)
+	t.Cleanup(func() { SetOverrides(nil) })
+	return root
+}
+
+func TestLoadLayers(t *testing.T) {
+	root := layeredProject(t,
+		"name: demo\ntech_stack: []\nreviewers:\n  default: auto\n  overrides:\n    design: human\ntesting:\n  style: tdd\n",
+		"tech_stack: [go, cobra]\nreviewers:\n  default: human\n  overrides:\n    phases: human\ntesting:\n  framework: vitest\n  min_cover: 70\n",
+	)
+	t.Setenv("FOREMAN_TESTING_FRAMEWORK", "go test")
+	t.Setenv("FOREMAN_REVIEWERS_OVERRIDES_REQUIREMENTS", "human")
+	t.Setenv("FOREMAN_HOOKS", "ignored")
+	if err := SetOverrides([]string{"testing.min_cover=90", "auto_advance=60"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, origins, err := LoadWithOrigins(TrackDir(root, MainTrack))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty project values fall through to the global config
+	if strings.Join(cfg.TechStack, ",") != "go,cobra" {
+		t.Errorf("expected global tech stack, got %v", cfg.TechStack)
+	}
+	if cfg.Reviewers.Default != "auto" {
+		t.Errorf("expected project reviewer to override global, got %s", cfg.Reviewers.Default)
+	}
+	for stage, want := range map[string]string{"design": "human", "phases": "human", "requirements": "human", "implementation": "auto"} {
+		if got := cfg.Reviewers.GetReviewer(stage); got != want {
+			t.Errorf("expected %s reviewer %s, got %s", stage, want, got)
+		}
+	}
+	if cfg.Testing.Style != "tdd" || cfg.Testing.Framework != "go test" || cfg.Testing.MinCover != 90 {
+		t.Errorf("unexpected testing settings: %+v", cfg.Testing)
+	}
+	if cfg.AutoAdvance != 60 {
+		t.Errorf("expected flag auto_advance 60, got %d", cfg.AutoAdvance)
+	}
+
+	want := map[string]string{
+		"name":                             LayerProject,
+		"tech_stack":                       LayerGlobal,
+		"reviewers.overrides.phases":       LayerGlobal,
+		"reviewers.overrides.requirements": "env (FOREMAN_REVIEWERS_OVERRIDES_REQUIREMENTS)",
+		"testing.framework":                "env (FOREMAN_TESTING_FRAMEWORK)",
+		"testing.min_cover":                "flag (-c testing.min_cover)",
+	}
+	for path, origin := range want {
+		if got := origins[path].String(); !strings.HasPrefix(got, origin) {
+			t.Errorf("expected %s from %s, got %q", path, origin, got)
+		}
+	}
+	if _, ok := origins["description"]; ok {
+		t.Error("expected empty description to be a default")
+	}
+
+	// Writers only see the project layer
+	project, err := LoadProject(TrackDir(root, MainTrack))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.TechStack) != 0 || project.AutoAdvance != 0 || project.Testing.Framework != "" {
+		t.Errorf("expected only project values, got %+v", project)
+	}
+}
+
+func TestLoadLayerErrors(t *testing.T) {
+	dir := TrackDir(layeredProject(t, "name: demo\n", ""), MainTrack)
+
+	for _, pair := range []string{"auto_advance", "bogus=1", "auto_advance=high", "testing.required=maybe"} {
+		if err := SetOverrides([]string{pair}); err == nil {
+			t.Errorf("expected -c %s to be rejected", pair)
+		}
+	}
+
+	t.Setenv("FOREMAN_AUTO_ADVANCE", "high")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "FOREMAN_AUTO_ADVANCE") {
+		t.Errorf("expected invalid env value error, got %v", err)
+	}
+	os.Unsetenv("FOREMAN_AUTO_ADVANCE")
+
+	global := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "foreman")
+	writePreset(t, global, "config.yaml", "tech_stack: [\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "global config") {
+		t.Errorf("expected global config parse error, got %v", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	for path, env := range map[string]string{
+		"testing.framework":          "FOREMAN_TESTING_FRAMEWORK",
+		"git.branch_prefix":          "FOREMAN_GIT_BRANCH_PREFIX",
+		"reviewers.overrides.design": "FOREMAN_REVIEWERS_OVERRIDES_",
+	} {
+		f, err := LookupField(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.EnvName() != env {
+			t.Errorf("expected %s for %s, got %s", env, path, f.EnvName())
+		}
+	}
+	for _, path := range []string{"testing", "reviewers.overrides", "reviewers.overrides.a.b", "nope"} {
+		if _, err := LookupField(path); err == nil {
+			t.Errorf("expected %s to be unknown", path)
+		}
+	}
+
+	values, err := Values(&Config{Name: "demo", TechStack: []string{"go", "yaml"}, Reviewers: Reviewers{Default: "auto"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, v := range values {
+		got[v.Path] = v.Value
+	}
+	if got["name"] != "demo" || got["tech_stack"] != "[go, yaml]" || got["description"] != `""` {
+		t.Errorf("unexpected values: %v", got)
+	}
+}
+
+func TestConfigGetSetUnset(t *testing.T) {
+	cfg := NewWithPreset("demo", PresetFull)
+
+	for key, value := range map[string]string{
+		"tech_stack":                 "go, yaml",
+		"auto_advance":               "70",
+		"testing.style":              "coverage",
+		"testing.min_cover":          "85",
+		"testing.required":           "yes",
+		"reviewers.overrides.design": "human",
+		"git.commits":                "true",
+	} {
+		err := cfg.Set(key, value)
+		if key == "testing.required" {
+			if err == nil {
+				t.Error("expected non-boolean testing.required to be rejected")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+	if strings.Join(cfg.TechStack, ",") != "go,yaml" || cfg.AutoAdvance != 70 || cfg.Testing.MinCover != 85 || !cfg.GitCommitsEnabled() {
+		t.Errorf("unexpected config after set: %+v", cfg)
+	}
+
+	for key, want := range map[string]string{
+		"tech_stack":                 "[go, yaml]",
+		"testing.min_cover":          "85",
+		"testing":                    "style: coverage\nmin_cover: 85",
+		"reviewers.overrides.design": "human",
+		"testing.framework":          "",
+	} {
+		if got, err := cfg.Get(key); err != nil || got != want {
+			t.Errorf("get %s: expected %q, got %q (%v)", key, want, got, err)
+		}
+	}
+
+	for key, value := range map[string]string{
+		"auto_advance":               "101",
+		"testing.style":              "sometimes",
+		"testing.min_cover":          "-1",
+		"workflow":                   "requirements,review,implementation",
+		"reviewers.default":          "robot",
+		"reviewers.overrides.design": "maybe",
+		"testing":                    "tdd",
+		"nope":                       "1",
+	} {
+		if err := cfg.Set(key, value); err == nil {
+			t.Errorf("expected %s=%s to be rejected", key, value)
+		}
+	}
+	if cfg.AutoAdvance != 70 || cfg.Testing.Style != TestingStyleCoverage {
+		t.Errorf("expected rejected changes to leave the config alone, got %+v", cfg)
+	}
+
+	// The design override keeps the workflow from dropping design
+	if err := cfg.Set("workflow", "requirements,implementation"); err == nil {
+		t.Error("expected workflow without the overridden design stage to be rejected")
+	}
+	if err := cfg.Unset("reviewers.overrides.design"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("workflow", "requirements,implementation"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overrides matching the default are dropped
+	if err := cfg.Set("reviewers.overrides.implementation", "auto"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Reviewers.Overrides["implementation"]; ok {
+		t.Errorf("expected redundant override to be dropped, got %v", cfg.Reviewers.Overrides)
+	}
+
+	if err := cfg.Unset("testing"); err != nil || cfg.Testing != nil {
+		t.Errorf("expected testing section to be removed, got %+v (%v)", cfg.Testing, err)
+	}
+	if err := cfg.Unset("bogus"); err == nil {
+		t.Error("expected unknown key to be rejected")
+	}
+}
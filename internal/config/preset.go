@@ -0,0 +1,292 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PresetSourceBuiltin is the Source of the presets shipped with foreman.
+const PresetSourceBuiltin = "built-in"
+
+// GateRules controls how a preset's gates behave.
+type GateRules struct {
+	Skip bool `yaml:"skip,omitempty"` // Approve every gate before implementation at init (like minimal)
+}
+
+// Preset is a named set of workflow settings applied by 'foreman init --preset'.
+// Besides the built-in presets, users can define their own in
+// ~/.config/foreman/presets/*.yaml or .foreman/presets/*.yaml.
+type Preset struct {
+	Name        string    `yaml:"name,omitempty"` // defaults to the file name
+	Description string    `yaml:"description,omitempty"`
+	Workflow    []string  `yaml:"workflow,omitempty"` // defaults to the full workflow
+	Reviewers   Reviewers `yaml:"reviewers,omitempty"`
+	AutoAdvance int       `yaml:"auto_advance,omitempty"`
+	Testing     *Testing  `yaml:"testing,omitempty"`
+	Gates       GateRules `yaml:"gates,omitempty"`
+	Source      string    `yaml:"-"` // "built-in" or the file the preset was loaded from
+}
+
+// builtinPresets are the presets shipped with foreman.
+var builtinPresets = []Preset{
+	{
+		Name:        PresetMinimal,
+		Description: "Script or hotfix: no gates, straight to implementation",
+		Workflow:    []string{"requirements", "implementation"},
+		Reviewers:   Reviewers{Default: "auto"},
+		AutoAdvance: 100, // Auto-advance everything
+		Gates:       GateRules{Skip: true},
+	},
+	{
+		Name:        PresetLight,
+		Description: "Small tool: requirements gate only, no design phase",
+		Workflow:    []string{"requirements", "implementation"},
+		Reviewers:   Reviewers{Default: "auto"},
+		AutoAdvance: 70, // Auto-advance at 70% confidence
+	},
+	{
+		Name:        PresetFull,
+		Description: "Product: full workflow with design and phases, human review for key stages",
+		Workflow:    []string{"requirements", "design", "phases", "implementation"},
+		Reviewers: Reviewers{
+			Default: "auto",
+			Overrides: map[string]string{
+				"requirements": "human",
+				"design":       "human",
+			},
+		},
+	},
+}
+
+// BuiltinPreset returns the built-in preset with the given name or alias, or nil.
+func BuiltinPreset(name string) *Preset {
+	name = NormalizePreset(name)
+	for i := range builtinPresets {
+		if builtinPresets[i].Name == name {
+			p := builtinPresets[i].clone()
+			p.Source = PresetSourceBuiltin
+			return p
+		}
+	}
+	return nil
+}
+
+// UserDir returns the per-user foreman directory: $XDG_CONFIG_HOME/foreman,
+// or ~/.config/foreman. It returns "" if the home directory is unknown.
+func UserDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "foreman")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "foreman")
+}
+
+// PresetDirs returns the directories user-defined presets are loaded from, in
+// increasing priority: the user's presets, then the project's. root may be ""
+// outside a project.
+func PresetDirs(root string) []string {
+	var dirs []string
+	if dir := UserDir(); dir != "" {
+		dirs = append(dirs, filepath.Join(dir, "presets"))
+	}
+	if root != "" {
+		dirs = append(dirs, filepath.Join(root, ".foreman", "presets"))
+	}
+	return dirs
+}
+
+// LoadPresets returns the built-in presets followed by the user-defined ones,
+// sorted by name within each group. A project preset replaces a user preset
+// of the same name; built-in presets cannot be redefined.
+func LoadPresets(root string) ([]*Preset, error) {
+	var presets []*Preset
+	for _, p := range builtinPresets {
+		presets = append(presets, BuiltinPreset(p.Name))
+	}
+
+	custom := make(map[string]*Preset)
+	for _, dir := range PresetDirs(root) {
+		files, err := presetFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			p, err := LoadPresetFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if BuiltinPreset(p.Name) != nil {
+				return nil, fmt.Errorf("%s: cannot redefine built-in preset %q", path, p.Name)
+			}
+			custom[p.Name] = p
+		}
+	}
+
+	var names []string
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		presets = append(presets, custom[name])
+	}
+	return presets, nil
+}
+
+// presetFiles lists the .yaml and .yml files of a presets directory, which may not exist.
+func presetFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// LoadPresetFile reads and validates a preset definition.
+func LoadPresetFile(path string) (*Preset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preset: %w", err)
+	}
+
+	var p Preset
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse preset %s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	p.Source = path
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid preset %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks a preset's settings and fills in defaults.
+func (p *Preset) Validate() error {
+	if len(p.Workflow) == 0 {
+		p.Workflow = []string{"requirements", "design", "phases", "implementation"}
+	}
+	if err := ValidateWorkflow(p.Workflow); err != nil {
+		return err
+	}
+
+	if p.Reviewers.Default == "" {
+		p.Reviewers.Default = "auto"
+	}
+	return validateSettings(p.Workflow, p.Reviewers, p.AutoAdvance, p.Testing)
+}
+
+// validateSettings checks the workflow settings shared by presets and configs.
+func validateSettings(workflow []string, reviewers Reviewers, autoAdvance int, testing *Testing) error {
+	if reviewers.Default != "auto" && reviewers.Default != "human" {
+		return fmt.Errorf("invalid default reviewer: %s (must be 'auto' or 'human')", reviewers.Default)
+	}
+	for stage, reviewer := range reviewers.Overrides {
+		if !slices.Contains(workflow, stage) {
+			return fmt.Errorf("reviewer set for %s, which is not in the workflow", stage)
+		}
+		if reviewer != "auto" && reviewer != "human" {
+			return fmt.Errorf("invalid reviewer for %s: %s (must be 'auto' or 'human')", stage, reviewer)
+		}
+	}
+
+	if autoAdvance < 0 || autoAdvance > 100 {
+		return fmt.Errorf("auto_advance must be between 0 and 100, got %d", autoAdvance)
+	}
+
+	if testing != nil {
+		switch testing.Style {
+		case "", TestingStyleTDD, TestingStyleCoverage, TestingStyleNone:
+		default:
+			return fmt.Errorf("invalid testing style: %s (must be tdd, coverage or none)", testing.Style)
+		}
+		if testing.MinCover < 0 || testing.MinCover > 100 {
+			return fmt.Errorf("testing.min_cover must be between 0 and 100, got %d", testing.MinCover)
+		}
+	}
+	return nil
+}
+
+// FindPreset looks up a preset by name or alias among the built-in and
+// user-defined presets. Unknown names are an error.
+func FindPreset(root, name string) (*Preset, error) {
+	if p := BuiltinPreset(name); p != nil {
+		return p, nil
+	}
+
+	presets, err := LoadPresets(root)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizePreset(name)
+	var names []string
+	for _, p := range presets {
+		if p.Name == normalized {
+			return p, nil
+		}
+		names = append(names, p.Name)
+	}
+	return nil, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// NewFromPreset creates a config with a preset's settings.
+func NewFromPreset(name string, p *Preset) *Config {
+	cfg := NewDefault(name)
+	cfg.ApplyPreset(p)
+	return cfg
+}
+
+// ApplyPreset replaces the config's workflow settings with the preset's.
+func (c *Config) ApplyPreset(p *Preset) {
+	q := p.clone()
+	c.Preset = q.Name
+	c.Workflow = q.Workflow
+	c.Reviewers = q.Reviewers
+	if c.Reviewers.Default == "" {
+		c.Reviewers.Default = "auto"
+	}
+	if c.Reviewers.Overrides == nil {
+		c.Reviewers.Overrides = make(map[string]string)
+	}
+	c.AutoAdvance = q.AutoAdvance
+	c.Testing = q.Testing
+}
+
+// clone returns a deep copy of the preset, so configs never share its slices and maps.
+func (p *Preset) clone() *Preset {
+	q := *p
+	q.Workflow = slices.Clone(p.Workflow)
+	q.Reviewers.Overrides = make(map[string]string, len(p.Reviewers.Overrides))
+	for stage, reviewer := range p.Reviewers.Overrides {
+		q.Reviewers.Overrides[stage] = reviewer
+	}
+	if p.Testing != nil {
+		testing := *p.Testing
+		q.Testing = &testing
+	}
+	return &q
+}
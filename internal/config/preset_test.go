@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePreset(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPresets(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	root := t.TempDir()
+
+	userDir := filepath.Join(xdg, "foreman", "presets")
+	projectDir := filepath.Join(root, ".foreman", "presets")
+	writePreset(t, userDir, "team.yaml", "description: user version\nworkflow: [requirements, implementation]\n")
+	writePreset(t, userDir, "docs.yml", "name: docs-only\ngates:\n  skip: true\n")
+	writePreset(t, userDir, "notes.txt", "ignored")
+	writePreset(t, projectDir, "team.yaml", "description: project version\nreviewers:\n  default: human\nauto_advance: 40\n")
+
+	presets, err := LoadPresets(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range presets {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "minimal,light,full,docs-only,team" {
+		t.Fatalf("unexpected presets: %v", names)
+	}
+
+	team, err := FindPreset(root, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Description != "project version" || team.Source != filepath.Join(projectDir, "team.yaml") {
+		t.Errorf("expected the project preset to win, got %+v", team)
+	}
+	if len(team.Workflow) != 4 {
+		t.Errorf("expected the full workflow by default, got %v", team.Workflow)
+	}
+
+	// Outside a project only the user's presets apply
+	if team, err := FindPreset("", "team"); err != nil || team.Description != "user version" {
+		t.Errorf("expected the user preset outside a project, got %+v (%v)", team, err)
+	}
+
+	cfg := NewFromPreset("demo", team)
+	if cfg.Preset != "team" || cfg.Reviewers.GetReviewer("design") != "human" || cfg.AutoAdvance != 40 {
+		t.Errorf("unexpected config from preset: %+v", cfg)
+	}
+	cfg.Reviewers.SetReviewer("design", "auto")
+	if again, _ := FindPreset(root, "team"); again.Reviewers.Default != "human" {
+		t.Error("expected configs not to share state with their preset")
+	}
+}
+
+func TestFindPresetErrors(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	userDir := filepath.Join(xdg, "foreman", "presets")
+
+	if _, err := FindPreset("", "nonexistent"); err == nil || !strings.Contains(err.Error(), "minimal, light, full") {
+		t.Errorf("expected unknown preset error listing presets, got %v", err)
+	}
+	if p, err := FindPreset("", PresetProduct); err != nil || p.Name != PresetFull {
+		t.Errorf("expected aliases to resolve, got %+v (%v)", p, err)
+	}
+
+	invalid := map[string]string{
+		"workflow.yaml":  "workflow: [requirements, review]\n",
+		"reviewer.yaml":  "reviewers:\n  overrides:\n    design: robot\n",
+		"stage.yaml":     "workflow: [requirements, implementation]\nreviewers:\n  overrides:\n    design: human\n",
+		"advance.yaml":   "auto_advance: 150\n",
+		"testing.yaml":   "testing:\n  style: sometimes\n",
+		"full.yaml":      "description: shadows a built-in\n",
+		"malformed.yaml": "workflow: [\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := os.RemoveAll(userDir); err != nil {
+				t.Fatal(err)
+			}
+			writePreset(t, userDir, name, content)
+			if _, err := LoadPresets(""); err == nil {
+				t.Errorf("expected %s to be rejected", name)
+			}
+		})
+	}
+
+	// Built-in presets resolve even when a user preset is broken
+	if _, err := FindPreset("", PresetLight); err != nil {
+		t.Errorf("expected built-in preset to resolve: %v", err)
+	}
+}
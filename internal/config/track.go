@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// MainTrack names the project's own flow in .foreman/. Named tracks live in
+// .foreman/tracks/<name>/ with the same layout: config.yaml, state.yaml,
+// requirements.md, designs/, phases/ and briefs/.
+const MainTrack = "main"
+
+// TracksDir is the directory of the named tracks inside .foreman/.
+const TracksDir = "tracks"
+
+var trackNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// ValidateTrackName checks that a track name can be used as a directory name.
+// Names start with a letter so that a track's branches, such as
+// phase/billing/1-setup, never collide with the main track's phase branches.
+func ValidateTrackName(name string) error {
+	if !trackNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid track name %q: start with a letter, then use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ResolveTrack returns the track a command works on: name if given (--track),
+// else the default_track setting of the project at root, read from
+// .foreman/config.yaml and the global, env and flag layers. Without the
+// setting, or outside a project, it is MainTrack.
+func ResolveTrack(root, name string) (string, error) {
+	if name != "" {
+		if err := ValidateTrackName(name); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	if root == "" {
+		return MainTrack, nil
+	}
+	dir := TrackDir(root, MainTrack)
+	if _, err := os.Stat(ConfigPath(dir)); err != nil {
+		return MainTrack, nil
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		return "", err
+	}
+	if cfg.DefaultTrack == "" {
+		return MainTrack, nil
+	}
+	return cfg.DefaultTrack, nil
+}
+
+// TrackDir returns the directory of a track, which holds the track's
+// config.yaml, state.yaml and documents: .foreman/ for the main track and
+// .foreman/tracks/<name>/ for a named one.
+func TrackDir(root, track string) string {
+	if track == "" || track == MainTrack {
+		return filepath.Join(root, ".foreman")
+	}
+	return filepath.Join(root, ".foreman", TracksDir, track)
+}
+
+// ListTracks returns the names of the named tracks of the project, sorted.
+// MainTrack is not included.
+func ListTracks(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, ".foreman", TracksDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && ValidateTrackName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// TrackExists reports whether a track has been created.
+func TrackExists(root, track string) bool {
+	info, err := os.Stat(filepath.Join(TrackDir(root, track), "state.yaml"))
+	return err == nil && !info.IsDir()
+}
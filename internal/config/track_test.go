@@ -0,0 +1,69 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTracks(t *testing.T) {
+	root := layeredProject(t, "name: demo\n", "")
+	writePreset(t, filepath.Join(root, ".foreman", "tracks", "billing"), "config.yaml", "name: demo\npreset: light\n")
+	writePreset(t, filepath.Join(root, ".foreman", "tracks", "billing"), "state.yaml", "current_stage: requirements\n")
+	writePreset(t, filepath.Join(root, ".foreman", "tracks", "Not A Track"), "state.yaml", "current_stage: requirements\n")
+
+	tracks, err := ListTracks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tracks, ",") != "billing" {
+		t.Errorf("expected the billing track, got %v", tracks)
+	}
+
+	track, err := ResolveTrack(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track != MainTrack || TrackDir(root, track) != filepath.Join(root, ".foreman") {
+		t.Errorf("expected the main track by default, got %s (%s)", track, TrackDir(root, track))
+	}
+
+	// --track selects a track's files
+	if track, err = ResolveTrack(root, "billing"); err != nil {
+		t.Fatal(err)
+	}
+	dir := TrackDir(root, track)
+	if ConfigPath(dir) != filepath.Join(root, ".foreman", "tracks", "billing", "config.yaml") {
+		t.Errorf("expected the track's config.yaml, got %s", ConfigPath(dir))
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Preset != PresetLight {
+		t.Errorf("expected the track's config to load, got %+v", cfg)
+	}
+	if _, err := ResolveTrack(root, "../escape"); err == nil {
+		t.Error("expected an invalid track name to be rejected")
+	}
+	if _, err := ResolveTrack(root, "1-setup"); err == nil {
+		t.Error("expected a track name like a phase ID to be rejected")
+	}
+
+	// default_track comes from the main config and the env and flag layers
+	writePreset(t, filepath.Join(root, ".foreman"), "config.yaml", "name: demo\ndefault_track: billing\n")
+	if track, _ := ResolveTrack(root, ""); track != "billing" || !TrackExists(root, "billing") {
+		t.Errorf("expected default_track to select billing, got %s", track)
+	}
+	t.Setenv("FOREMAN_DEFAULT_TRACK", MainTrack)
+	if track, _ := ResolveTrack(root, ""); track != MainTrack {
+		t.Errorf("expected FOREMAN_DEFAULT_TRACK to win, got %s", track)
+	}
+	if track, _ := ResolveTrack(root, "billing"); track != "billing" {
+		t.Errorf("expected --track to win over default_track, got %s", track)
+	}
+
+	if err := (&Config{DefaultTrack: "Bad Name"}).Validate(); err == nil {
+		t.Error("expected an invalid default_track to be rejected")
+	}
+}
@@ -0,0 +1,378 @@
+// Package doctor finds inconsistencies between a project's config.yaml,
+// state.yaml and files that no single command detects, and repairs the
+// ones that can be fixed without losing work.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// Problem is one inconsistency found in a project.
+type Problem struct {
+	Message     string // what is wrong
+	Explanation string // why it matters
+	Repair      string // what Fix does about it; empty if it needs a manual fix
+
+	fix func(r *Report) error
+}
+
+// Fixable reports whether Fix can repair the problem.
+func (p Problem) Fixable() bool {
+	return p.fix != nil
+}
+
+// Report holds the problems found in a project.
+type Report struct {
+	Dir      string // the track's directory (see config.TrackDir)
+	Problems []Problem
+
+	cfg          *config.Config
+	st           *state.State
+	stateChanged bool
+}
+
+// Fixable returns the number of problems Fix can repair.
+func (r *Report) Fixable() int {
+	n := 0
+	for _, p := range r.Problems {
+		if p.Fixable() {
+			n++
+		}
+	}
+	return n
+}
+
+// Check inspects the track in dir. The track's config (without the global,
+// env and flag layers) is the reference for the workflow.
+func Check(dir string) (*Report, error) {
+	cfg, err := config.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	st, err := state.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Dir: dir, cfg: cfg, st: st}
+	r.checkConfig()
+	r.checkWorkflow()
+	r.checkGates()
+	if err := r.checkPhases(); err != nil {
+		return nil, err
+	}
+	if err := r.checkBriefs(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Fix applies the repairs of the fixable problems and saves state.yaml.
+// It returns the problems it fixed.
+func (r *Report) Fix() ([]Problem, error) {
+	var fixed []Problem
+	for _, p := range r.Problems {
+		if !p.Fixable() {
+			continue
+		}
+		if err := p.fix(r); err != nil {
+			return fixed, fmt.Errorf("failed to fix %q: %w", p.Message, err)
+		}
+		fixed = append(fixed, p)
+	}
+	if r.stateChanged {
+		if err := state.Save(r.Dir, r.st); err != nil {
+			return fixed, err
+		}
+	}
+	return fixed, nil
+}
+
+func (r *Report) add(p Problem) {
+	r.Problems = append(r.Problems, p)
+}
+
+// changeState returns a fix that edits state.yaml.
+func changeState(edit func(st *state.State)) func(r *Report) error {
+	return func(r *Report) error {
+		edit(r.st)
+		r.stateChanged = true
+		return nil
+	}
+}
+
+func (r *Report) checkConfig() {
+	if err := r.cfg.Validate(); err != nil {
+		r.add(Problem{
+			Message:     fmt.Sprintf("config.yaml is invalid: %v", err),
+			Explanation: "Commands may misbehave with settings outside their allowed values. Correct it with 'foreman config set'.",
+		})
+	}
+	if !r.st.MinimalMode && r.st.Confidence != r.cfg.AutoAdvance {
+		want := r.cfg.AutoAdvance
+		r.add(Problem{
+			Message:     fmt.Sprintf("state.yaml auto-advance threshold is %d, config.yaml says %d", r.st.Confidence, want),
+			Explanation: "Gates auto-advance based on the threshold in state.yaml, so changes to auto_advance in config.yaml have no effect.",
+			Repair:      fmt.Sprintf("set the threshold in state.yaml to %d", want),
+			fix:         changeState(func(st *state.State) { st.Confidence = want }),
+		})
+	}
+}
+
+// checkWorkflow compares the stages of config.yaml and state.yaml and
+// checks the current stage against them.
+func (r *Report) checkWorkflow() {
+	workflow := r.cfg.GetWorkflow()
+	resync := changeState(func(st *state.State) { st.SetWorkflow(workflow) })
+
+	if active := r.st.GetActiveStages(); !slices.Equal(active, workflow) {
+		r.add(Problem{
+			Message:     fmt.Sprintf("workflow differs: config.yaml has %s, state.yaml has %s", strings.Join(workflow, " → "), strings.Join(active, " → ")),
+			Explanation: "Gates follow state.yaml while briefs, templates and reviewers follow config.yaml, so commands disagree about which stages exist.",
+			Repair:      "switch state.yaml to the config.yaml workflow, keeping approved gates",
+			fix:         resync,
+		})
+	}
+
+	if !slices.Contains(workflow, r.st.CurrentStage) {
+		r.add(Problem{
+			Message:     fmt.Sprintf("current stage %q is not in the workflow (%s)", r.st.CurrentStage, strings.Join(workflow, ", ")),
+			Explanation: "No gate can be validated or approved for a stage outside the workflow, so the project cannot advance.",
+			Repair:      "make the first stage whose gate is not approved the current stage",
+			fix:         resync,
+		})
+		return
+	}
+
+	for _, stage := range workflow[:slices.Index(workflow, r.st.CurrentStage)] {
+		if gate := r.st.Gates[stage]; gate == nil || gate.Status != "approved" {
+			r.add(Problem{
+				Message:     fmt.Sprintf("current stage is %s but the %s gate is not approved", r.st.CurrentStage, stage),
+				Explanation: "Stages are worked through in order; the project skipped a gate, e.g. through a hand edit of state.yaml.",
+				Repair:      fmt.Sprintf("go back to the %s stage", stage),
+				fix:         resync,
+			})
+			return
+		}
+	}
+
+	if gate := r.st.Gates[r.st.CurrentStage]; gate != nil && gate.Status == "blocked" {
+		r.add(Problem{
+			Message:     fmt.Sprintf("the gate of the current stage %s is blocked", r.st.CurrentStage),
+			Explanation: "A blocked gate cannot be validated or approved, so the project cannot advance.",
+			Repair:      fmt.Sprintf("open the %s gate", r.st.CurrentStage),
+			fix:         resync,
+		})
+	}
+}
+
+func (r *Report) checkGates() {
+	workflow := r.cfg.GetWorkflow()
+
+	var extra []string
+	for name := range r.st.Gates {
+		if !slices.Contains(workflow, name) {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) > 0 {
+		slices.SortFunc(extra, func(a, b string) int { return state.GetStageIndex(a) - state.GetStageIndex(b) })
+		r.add(Problem{
+			Message:     fmt.Sprintf("state.yaml has gates for stages outside the workflow: %s", strings.Join(extra, ", ")),
+			Explanation: "Older versions of foreman added all four gates to every project. The extra gates are never used but show up in hand edits and tools reading state.yaml.",
+			Repair:      "remove the extra gates",
+			fix: changeState(func(st *state.State) {
+				for _, name := range extra {
+					delete(st.Gates, name)
+				}
+			}),
+		})
+	}
+
+	for _, stage := range workflow {
+		gate := r.st.Gates[stage]
+		if gate == nil {
+			continue
+		}
+		if !state.IsValidGateStatus(gate.Status) {
+			r.add(Problem{
+				Message:     fmt.Sprintf("gate %s has invalid status %q", stage, gate.Status),
+				Explanation: "Gate commands only understand blocked, open, pending-review and approved. Set the status in state.yaml by hand.",
+			})
+		} else if gate.Status == "approved" && (gate.ApprovedAt == nil || gate.ApprovedBy == "") {
+			r.add(Problem{
+				Message:     fmt.Sprintf("gate %s is approved without approved_at/approved_by", stage),
+				Explanation: "Approvals record who approved a gate and when. Add the missing fields to state.yaml, or reopen the gate and approve it again.",
+			})
+		}
+	}
+}
+
+func (r *Report) checkPhases() error {
+	names, err := project.ListPhaseNames(r.Dir)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, phase := range r.st.Phases {
+		if seen[phase.Name] {
+			r.add(Problem{
+				Message:     fmt.Sprintf("phase %s is listed more than once in state.yaml", phase.Name),
+				Explanation: "Status changes only reach the first entry. Remove the duplicate from state.yaml by hand.",
+			})
+			continue
+		}
+		seen[phase.Name] = true
+
+		if !state.IsValidPhaseStatus(phase.Status) {
+			r.add(Problem{
+				Message:     fmt.Sprintf("phase %s has invalid status %q", phase.Name, phase.Status),
+				Explanation: "Phases are planned, in-progress or done. Set it with 'foreman phase <name> <status>'.",
+			})
+		}
+		for _, task := range phase.Tasks {
+			if !state.IsValidPhaseStatus(task.Status) {
+				r.add(Problem{
+					Message:     fmt.Sprintf("task %s/%s has invalid status %q", phase.Name, task.ID, task.Status),
+					Explanation: "Tasks are planned, in-progress or done. Set it with 'foreman task <phase>/<task> <status>'.",
+				})
+			}
+		}
+		if done, total := phase.TaskProgress(); phase.Status == "done" && done < total {
+			r.add(Problem{
+				Message:     fmt.Sprintf("phase %s is done but %d of its tasks are not", phase.Name, total-done),
+				Explanation: "Finish the tasks with 'foreman task', or move the phase back to in-progress.",
+			})
+		}
+
+		if slices.Contains(names, phase.Name) {
+			continue
+		}
+		p := Problem{
+			Message:     fmt.Sprintf("phase %s has no plan file (%s)", phase.Name, project.PhasePlanPath(r.Dir, phase.Name)),
+			Explanation: "Briefs and gates read the phase from its plan, so the phase can't be briefed or validated.",
+		}
+		if phase.Status == "planned" {
+			name := phase.Name
+			p.Repair = "remove the phase from state.yaml (it was never started)"
+			p.fix = changeState(func(st *state.State) {
+				st.Phases = slices.DeleteFunc(st.Phases, func(ph state.Phase) bool { return ph.Name == name })
+			})
+		} else {
+			p.Explanation += " Restore the plan, or remove the phase from state.yaml by hand; it has progress that would be lost."
+		}
+		r.add(p)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		r.add(Problem{
+			Message:     fmt.Sprintf("phase plans not tracked in state.yaml: %s", strings.Join(missing, ", ")),
+			Explanation: "Phases only get a status once they're synced; 'foreman status' and the gates don't know them yet.",
+			Repair:      "add the phases to state.yaml",
+			fix: changeState(func(st *state.State) {
+				for _, name := range missing {
+					st.AddPhase(name)
+				}
+				sortPhases(st)
+			}),
+		})
+	}
+
+	invalid, err := project.InvalidPhaseFiles(r.Dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range invalid {
+		r.add(Problem{
+			Message:     fmt.Sprintf("phases/%s is not a valid phase name", file),
+			Explanation: "Phase plans are named like 1-setup.md or 2.1-auth.md; other files are ignored. Rename the file.",
+		})
+	}
+	return nil
+}
+
+// sortPhases puts the phases of the state in plan order.
+func sortPhases(st *state.State) {
+	var names []string
+	for _, phase := range st.Phases {
+		names = append(names, phase.Name)
+	}
+	project.SortPhaseNames(names)
+	slices.SortStableFunc(st.Phases, func(a, b state.Phase) int {
+		return slices.Index(names, a.Name) - slices.Index(names, b.Name)
+	})
+}
+
+// briefExtensions are the file endings of the brief formats.
+var briefExtensions = []string{".agents.md", ".md", ".xml", ".json"}
+
+// checkBriefs looks for briefs of phases that no longer exist: phases without
+// a plan that were never started. Briefs of started phases are kept.
+func (r *Report) checkBriefs() error {
+	briefsDir := project.BriefsPath(r.Dir)
+	if _, err := os.Stat(briefsDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	names, err := project.ListPhaseNames(r.Dir)
+	if err != nil {
+		return err
+	}
+	phases := make(map[string]bool)
+	for _, name := range names {
+		phases[name] = true
+	}
+	for _, phase := range r.st.Phases {
+		if phase.Status != "planned" {
+			phases[phase.Name] = true
+		}
+	}
+
+	return filepath.WalkDir(briefsDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(briefsDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		name := ""
+		for _, ext := range briefExtensions {
+			if strings.HasSuffix(rel, ext) {
+				name = strings.TrimSuffix(rel, ext)
+				break
+			}
+		}
+		// Quick mode briefs ("impl") and other files aren't phase briefs
+		if !project.IsPhaseName(name) || phases[name] {
+			return nil
+		}
+
+		r.add(Problem{
+			Message:     fmt.Sprintf("brief briefs/%s is for phase %s, which no longer exists", rel, name),
+			Explanation: "Coding agents handed this brief would work on a phase that was removed from the plan.",
+			Repair:      "delete the brief",
+			fix: func(r *Report) error {
+				return os.Remove(path)
+			},
+		})
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// TestMain keeps the user's global config and FOREMAN_* variables out of the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "foreman-user-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, config.EnvPrefix) {
+			os.Unsetenv(key)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// initProject creates a project and returns the directory of its main track.
+func initProject(t *testing.T, preset string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	root, err := project.InitWithOptions(t.TempDir(), project.InitOptions{Name: "doctor", Preset: preset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project.ForemanPath(root)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func messages(problems []Problem) string {
+	var lines []string
+	for _, p := range problems {
+		lines = append(lines, p.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestCheckHealthyProjects(t *testing.T) {
+	for _, preset := range []string{config.PresetMinimal, config.PresetLight, config.PresetFull} {
+		dir := initProject(t, preset)
+		report, err := Check(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Problems) != 0 {
+			t.Errorf("expected a new %s project to be healthy, got:\n%s", preset, messages(report.Problems))
+		}
+	}
+}
+
+func TestCheckAndFix(t *testing.T) {
+	dir := initProject(t, config.PresetLight)
+
+	// A light project whose state.yaml still has the full workflow
+	writeFile(t, state.StatePath(dir), `current_stage: design
+gates:
+    requirements:
+        status: approved
+    design:
+        status: open
+    phases:
+        status: blocked
+    implementation:
+        status: sometimes
+phases:
+    - name: 1-setup
+      status: planned
+    - name: 2-api
+      status: in-progress
+    - name: 2-api
+      status: done
+confidence: 10
+`)
+	writeFile(t, project.PhasePlanPath(dir, "3-deploy"), "# Phase 3: Deploy\n")
+	writeFile(t, filepath.Join(project.PhasesPath(dir), "notes.md"), "notes\n")
+	writeFile(t, filepath.Join(project.BriefsPath(dir), "1-setup.xml"), "<brief/>\n")
+	writeFile(t, filepath.Join(project.BriefsPath(dir), "2-api.md"), "# Brief\n")
+	writeFile(t, filepath.Join(project.BriefsPath(dir), "impl.md"), "# Quick brief\n")
+
+	report, err := Check(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixable := map[string]bool{
+		"auto-advance threshold is 10":                          true,
+		"workflow differs":                                      true,
+		`current stage "design" is not in the workflow`:         true,
+		"gates for stages outside the workflow: design, phases": true,
+		`gate implementation has invalid status "sometimes"`:    false,
+		"gate requirements is approved without":                 false,
+		"phase 2-api is listed more than once":                  false,
+		"phase 1-setup has no plan file":                        true,
+		"phase 2-api has no plan file":                          false,
+		"not tracked in state.yaml: 3-deploy":                   true,
+		"phases/notes.md is not a valid phase name":             false,
+		"briefs/1-setup.xml is for phase 1-setup":               true,
+	}
+	if len(report.Problems) != len(fixable) {
+		t.Errorf("expected %d problems, got:\n%s", len(fixable), messages(report.Problems))
+	}
+	for fragment, canFix := range fixable {
+		found := false
+		for _, p := range report.Problems {
+			if strings.Contains(p.Message, fragment) {
+				found = true
+				if p.Fixable() != canFix {
+					t.Errorf("expected %q fixable=%v", p.Message, canFix)
+				}
+				if p.Explanation == "" || p.Fixable() && p.Repair == "" {
+					t.Errorf("expected %q to be explained", p.Message)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", fragment, messages(report.Problems))
+		}
+	}
+
+	fixed, err := report.Fix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed) != report.Fixable() {
+		t.Errorf("expected %d fixes, got %d", report.Fixable(), len(fixed))
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentStage != "implementation" || st.Confidence != 70 || len(st.Gates) != 2 {
+		t.Errorf("expected state to follow the light workflow, got %+v", st)
+	}
+	var phases []string
+	for _, phase := range st.Phases {
+		phases = append(phases, phase.Name)
+	}
+	if strings.Join(phases, ",") != "2-api,2-api,3-deploy" {
+		t.Errorf("expected unstarted phase removed and new plan added, got %v", phases)
+	}
+	if _, err := os.Stat(filepath.Join(project.BriefsPath(dir), "1-setup.xml")); !os.IsNotExist(err) {
+		t.Error("expected stale brief to be deleted")
+	}
+	for _, kept := range []string{"2-api.md", "impl.md"} {
+		if _, err := os.Stat(filepath.Join(project.BriefsPath(dir), kept)); err != nil {
+			t.Errorf("expected brief %s to be kept: %v", kept, err)
+		}
+	}
+
+	// Only the problems needing a manual fix remain
+	again, err := Check(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Fixable() != 0 || len(again.Problems) != len(report.Problems)-len(fixed) {
+		t.Errorf("expected only manual problems after --fix, got:\n%s", messages(again.Problems))
+	}
+}
@@ -12,14 +12,15 @@ import (
 
 // ValidationResult represents the result of a gate validation check.
 type ValidationResult struct {
-	Passed  bool
-	Message string
-	Details []string
+	Passed   bool
+	Message  string
+	Details  []string
+	Warnings []string // problems worth fixing that don't block the gate
 }
 
 // ValidateRequirements checks if the requirements stage is ready to pass.
-func ValidateRequirements(root string) *ValidationResult {
-	reqPath := filepath.Join(project.ForemanPath(root), "requirements.md")
+func ValidateRequirements(root, dir string) *ValidationResult {
+	reqPath := project.RequirementsPath(dir)
 	
 	// Check if file exists
 	if _, err := os.Stat(reqPath); os.IsNotExist(err) {
@@ -70,6 +71,15 @@ func ValidateRequirements(root string) *ValidationResult {
 		}
 	}
 	
+	// Included files must exist and not include each other in a cycle
+	if broken := brokenIncludes(root, dir, reqPath); len(broken) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Requirements document has broken includes",
+			Details: broken,
+		}
+	}
+	
 	return &ValidationResult{
 		Passed:  true,
 		Message: "Requirements stage is ready",
@@ -81,8 +91,8 @@ func ValidateRequirements(root string) *ValidationResult {
 }
 
 // ValidateDesign checks if the design stage is ready to pass.
-func ValidateDesign(root string) *ValidationResult {
-	designsDir := filepath.Join(project.ForemanPath(root), "designs")
+func ValidateDesign(root, dir string) *ValidationResult {
+	designsDir := project.DesignsPath(dir)
 	
 	// Check if designs directory exists
 	if _, err := os.Stat(designsDir); os.IsNotExist(err) {
@@ -96,8 +106,8 @@ func ValidateDesign(root string) *ValidationResult {
 		}
 	}
 	
-	// Check for .md files in designs directory
-	entries, err := os.ReadDir(designsDir)
+	// Check for .md files in designs directory and its subdirectories
+	names, err := project.ListDesignFiles(dir)
 	if err != nil {
 		return &ValidationResult{
 			Passed:  false,
@@ -107,30 +117,35 @@ func ValidateDesign(root string) *ValidationResult {
 	}
 	
 	var mdFiles []string
+	var designPaths []string
+	var badFrontmatter []string
+	var placeholders []string
 	totalContent := 0
 	
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		
-		if !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
-		
+	for _, name := range names {
 		// Check file content
-		path := filepath.Join(designsDir, entry.Name())
+		path := filepath.Join(designsDir, filepath.FromSlash(name))
+		designPaths = append(designPaths, path)
 		data, err := os.ReadFile(path)
 		if err != nil {
 			continue
 		}
 		
+		var meta project.DesignMeta
+		body, err := project.ParseFrontmatter(string(data), &meta)
+		if err != nil {
+			badFrontmatter = append(badFrontmatter, fmt.Sprintf("designs/%s: %v", name, err))
+		}
+		for _, placeholder := range project.Placeholders(body) {
+			placeholders = append(placeholders, fmt.Sprintf("designs/%s: %s", name, placeholder))
+		}
+		
 		content := strings.TrimSpace(string(data))
 		if len(content) < 20 { // Minimal content check
 			continue
 		}
 		
-		mdFiles = append(mdFiles, entry.Name())
+		mdFiles = append(mdFiles, name)
 		totalContent += len(content)
 	}
 	
@@ -146,6 +161,53 @@ func ValidateDesign(root string) *ValidationResult {
 		}
 	}
 	
+	if len(badFrontmatter) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Design documents have invalid frontmatter",
+			Details: badFrontmatter,
+		}
+	}
+	
+	// Scaffolded and blueprint designs mark the text to write in _italics_
+	if len(placeholders) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Design documents contain placeholder text",
+			Details: append([]string{
+				"Replace the _italic_ template placeholders with the actual design:",
+			}, placeholders...),
+		}
+	}
+	
+	// designs/index.yaml may only list existing designs
+	if missing := missingIndexEntries(dir); len(missing) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "designs/index.yaml is invalid",
+			Details: missing,
+		}
+	}
+	
+	if broken := brokenIncludes(root, dir, designPaths...); len(broken) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Design documents have broken includes",
+			Details: broken,
+		}
+	}
+	
+	// Phase plans may select designs by name; every referenced file must exist
+	if missing := missingDesignReferences(dir); len(missing) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase plans reference missing design documents",
+			Details: append([]string{
+				"Create the referenced files in designs/ or fix the phase frontmatter:",
+			}, missing...),
+		}
+	}
+	
 	return &ValidationResult{
 		Passed:  true,
 		Message: "Design stage is ready",
@@ -156,9 +218,70 @@ func ValidateDesign(root string) *ValidationResult {
 	}
 }
 
+// missingIndexEntries lists problems with designs/index.yaml: parse errors and
+// entries naming designs that do not exist.
+func missingIndexEntries(dir string) []string {
+	index, err := project.ReadDesignIndex(dir)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if index == nil {
+		return nil
+	}
+	
+	var missing []string
+	for _, name := range index.Order {
+		if _, err := os.Stat(filepath.Join(project.DesignsPath(dir), filepath.FromSlash(name))); os.IsNotExist(err) {
+			missing = append(missing, fmt.Sprintf("%s lists designs/%s, which does not exist", project.DesignIndexFile, name))
+		}
+	}
+	return missing
+}
+
+// missingDesignReferences lists designs named in phase frontmatter that do not exist.
+func missingDesignReferences(dir string) []string {
+	phaseNames, err := project.ListPhaseNames(dir)
+	if err != nil {
+		return nil
+	}
+	
+	var missing []string
+	for _, phaseName := range phaseNames {
+		meta, err := project.ReadPhaseMeta(dir, phaseName)
+		if err != nil {
+			missing = append(missing, err.Error())
+			continue
+		}
+		for _, ref := range meta.Designs {
+			name := project.NormalizeDesignName(ref)
+			if _, err := os.Stat(filepath.Join(project.DesignsPath(dir), filepath.FromSlash(name))); os.IsNotExist(err) {
+				missing = append(missing, fmt.Sprintf("%s: designs/%s not found", phaseName, name))
+			}
+		}
+	}
+	
+	return missing
+}
+
+// brokenIncludes lists the include directives in the given files of the
+// track in dir that cannot be expanded.
+func brokenIncludes(root, dir string, paths ...string) []string {
+	var broken []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		for _, err := range project.CheckIncludes(root, path) {
+			broken = append(broken, fmt.Sprintf("%s: %v", filepath.ToSlash(rel), err))
+		}
+	}
+	return broken
+}
+
 // ValidatePhases checks if the phases stage is ready to pass.
-func ValidatePhases(root string) *ValidationResult {
-	phasesDir := filepath.Join(project.ForemanPath(root), "phases")
+func ValidatePhases(root, dir string) *ValidationResult {
+	phasesDir := project.PhasesPath(dir)
 	
 	// Check if phases directory exists
 	if _, err := os.Stat(phasesDir); os.IsNotExist(err) {
@@ -207,9 +330,18 @@ func ValidatePhases(root string) *ValidationResult {
 			},
 		}
 	}
+	if placeholders := project.Placeholders(project.StripFrontmatter(overviewContent)); len(placeholders) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase overview contains placeholder text",
+			Details: append([]string{
+				"Replace the _italic_ template placeholders in phases/overview.md:",
+			}, placeholders...),
+		}
+	}
 	
-	// Check for individual phase plans
-	entries, err := os.ReadDir(phasesDir)
+	// Check for individual phase plans (including nested ones)
+	phaseNames, err := project.ListPhaseNames(dir)
 	if err != nil {
 		return &ValidationResult{
 			Passed:  false,
@@ -218,39 +350,77 @@ func ValidatePhases(root string) *ValidationResult {
 		}
 	}
 	
-	var phaseFiles []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		
-		name := entry.Name()
-		if name == "overview.md" {
-			continue
-		}
-		
-		if !strings.HasSuffix(name, ".md") {
-			continue
+	// Plans that don't follow the naming convention would never become phases
+	invalid, err := project.InvalidPhaseFiles(dir)
+	if err == nil && len(invalid) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase plan files with invalid names",
+			Details: append([]string{
+				"Rename these files to <number>-<name>.md (e.g. 1-setup.md, 10-deploy.md, 2.1-auth.md):",
+			}, invalid...),
 		}
-		
-		// Check if it looks like a phase file (e.g., "1-setup.md", "2-backend.md")
-		if len(name) >= 3 && name[1] == '-' {
-			phaseFiles = append(phaseFiles, name)
+	}
+	
+	// "2.1-auth" and "2-backend/1-auth" are both phase 2.1
+	if duplicates := duplicatePhaseNumbers(phaseNames); len(duplicates) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase plans share a phase number",
+			Details: append([]string{
+				"Renumber these plans so every phase has its own number:",
+			}, duplicates...),
 		}
 	}
 	
+	var phaseFiles []string
+	for _, name := range phaseNames {
+		phaseFiles = append(phaseFiles, name+".md")
+	}
+	
 	if len(phaseFiles) == 0 {
 		return &ValidationResult{
 			Passed:  false,
 			Message: "No phase plan files found",
 			Details: []string{
 				"Create individual phase plans in phases/ directory",
-				"Use naming convention: 1-setup.md, 2-backend.md, etc.",
+				"Use naming convention: 1-setup.md, 2-backend.md, 10-deploy.md, 2.1-auth.md or 2-backend/1-auth.md",
 				"Each phase should have its own detailed plan",
+				"Run 'foreman phases generate' to create them from the phases listed in overview.md",
 			},
 		}
 	}
 	
+	// A plan's goal says what the phase delivers; a scaffold leaves it as a placeholder
+	var unfilled []string
+	for _, name := range phaseNames {
+		goal, ok := project.PlanGoal(project.ReadFileContent(project.PhasePlanPath(dir, name), ""))
+		if ok && (goal == "" || len(project.Placeholders(goal)) > 0) {
+			unfilled = append(unfilled, name+".md")
+		}
+	}
+	if len(unfilled) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase plans have no goal",
+			Details: append([]string{
+				"Replace the placeholder in the '## Goal' section of:",
+			}, unfilled...),
+		}
+	}
+	
+	planPaths := []string{overviewPath}
+	for _, name := range phaseFiles {
+		planPaths = append(planPaths, filepath.Join(phasesDir, filepath.FromSlash(name)))
+	}
+	if broken := brokenIncludes(root, dir, planPaths...); len(broken) > 0 {
+		return &ValidationResult{
+			Passed:  false,
+			Message: "Phase plans have broken includes",
+			Details: broken,
+		}
+	}
+	
 	return &ValidationResult{
 		Passed:  true,
 		Message: "Phases stage is ready",
@@ -258,7 +428,51 @@ func ValidatePhases(root string) *ValidationResult {
 			"overview.md exists with content",
 			fmt.Sprintf("Found %d phase plans: %s", len(phaseFiles), strings.Join(phaseFiles, ", ")),
 		},
+		Warnings: overviewWarnings(root, dir),
+	}
+}
+
+// duplicatePhaseNumbers lists the phase numbers used by more than one plan,
+// with the plans using them.
+func duplicatePhaseNumbers(names []string) []string {
+	byNumber := make(map[string][]string)
+	var numbers []string
+	for _, name := range names {
+		id, err := project.ParsePhaseID(name)
+		if err != nil {
+			continue
+		}
+		if _, seen := byNumber[id.Number()]; !seen {
+			numbers = append(numbers, id.Number())
+		}
+		byNumber[id.Number()] = append(byNumber[id.Number()], name)
+	}
+
+	var duplicates []string
+	for _, number := range numbers {
+		if plans := byNumber[number]; len(plans) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("phase %s: %s", number, strings.Join(plans, ", ")))
+		}
+	}
+	return duplicates
+}
+
+// overviewWarnings reports where the phases listed in overview.md and the
+// phase plan files disagree. Overviews without a parseable list are not checked.
+func overviewWarnings(root, dir string) []string {
+	diff, err := project.DiffOverview(root, dir)
+	if err != nil || len(diff.Listed) == 0 {
+		return nil
+	}
+
+	var warnings []string
+	for _, phase := range diff.Missing {
+		warnings = append(warnings, fmt.Sprintf("overview.md lists phase %s (%s) but it has no plan file; run 'foreman phases generate'", phase.Number(), phase.Title))
+	}
+	for _, name := range diff.Unlisted {
+		warnings = append(warnings, fmt.Sprintf("phases/%s.md is not listed in overview.md", name))
 	}
+	return warnings
 }
 
 // ValidateImplementation checks if the implementation stage is ready to pass.
@@ -303,14 +517,14 @@ func ValidateImplementation(root string, s *state.State) *ValidationResult {
 }
 
 // ValidateStage validates a specific stage's readiness.
-func ValidateStage(root, stage string, s *state.State) (*ValidationResult, error) {
+func ValidateStage(root, dir, stage string, s *state.State) (*ValidationResult, error) {
 	switch stage {
 	case "requirements":
-		return ValidateRequirements(root), nil
+		return ValidateRequirements(root, dir), nil
 	case "design":
-		return ValidateDesign(root), nil
+		return ValidateDesign(root, dir), nil
 	case "phases":
-		return ValidatePhases(root), nil
+		return ValidatePhases(root, dir), nil
 	case "implementation":
 		return ValidateImplementation(root, s), nil
 	default:
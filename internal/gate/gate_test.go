@@ -3,6 +3,7 @@ package gate
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/thinkshake/foreman/internal/state"
@@ -33,9 +34,10 @@ func setupTestProject(t *testing.T) string {
 func TestValidateRequirements(t *testing.T) {
 	root := setupTestProject(t)
 	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
 
 	// Test missing requirements file
-	result := ValidateRequirements(root)
+	result := ValidateRequirements(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with missing requirements.md")
 	}
@@ -46,7 +48,7 @@ func TestValidateRequirements(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result = ValidateRequirements(root)
+	result = ValidateRequirements(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with empty requirements.md")
 	}
@@ -57,7 +59,7 @@ func TestValidateRequirements(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result = ValidateRequirements(root)
+	result = ValidateRequirements(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with placeholder content")
 	}
@@ -85,7 +87,7 @@ Build a CLI tool for project management.
 		t.Fatal(err)
 	}
 
-	result = ValidateRequirements(root)
+	result = ValidateRequirements(root, dir)
 	if !result.Passed {
 		t.Errorf("expected validation to pass with valid requirements: %s", result.Message)
 	}
@@ -94,9 +96,10 @@ Build a CLI tool for project management.
 func TestValidateDesign(t *testing.T) {
 	root := setupTestProject(t)
 	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
 
 	// Test missing designs directory (already created in setup)
-	result := ValidateDesign(root)
+	result := ValidateDesign(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with empty designs directory")
 	}
@@ -107,7 +110,7 @@ func TestValidateDesign(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result = ValidateDesign(root)
+	result = ValidateDesign(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with no markdown files")
 	}
@@ -117,7 +120,7 @@ func TestValidateDesign(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result = ValidateDesign(root)
+	result = ValidateDesign(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with empty markdown file")
 	}
@@ -150,20 +153,162 @@ The system uses a CLI-based architecture with multiple stages.
 		t.Fatal(err)
 	}
 
-	result = ValidateDesign(root)
+	result = ValidateDesign(root, dir)
 	if !result.Passed {
 		t.Errorf("expected validation to pass with valid design files: %s", result.Message)
 	}
 }
 
+func TestValidateDesignReferences(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	designsDir := filepath.Join(root, ".foreman", "designs")
+	phasesDir := filepath.Join(root, ".foreman", "phases")
+
+	apiContent := "# API Design\n\nREST endpoints for the service."
+	if err := os.WriteFile(filepath.Join(designsDir, "api.md"), []byte(apiContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	phaseContent := "---\ndesigns: [api.md, data-model.md]\n---\n# Phase 1: Backend\n"
+	if err := os.WriteFile(filepath.Join(phasesDir, "1-backend.md"), []byte(phaseContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// data-model.md is referenced but missing
+	result := ValidateDesign(root, dir)
+	if result.Passed {
+		t.Error("expected validation to fail with a missing referenced design")
+	}
+
+	found := false
+	for _, detail := range result.Details {
+		if strings.Contains(detail, "data-model.md") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected details to name data-model.md, got %v", result.Details)
+	}
+
+	// References without the .md suffix resolve too
+	modelContent := "# Data Model\n\nTables and relations."
+	if err := os.WriteFile(filepath.Join(designsDir, "data-model.md"), []byte(modelContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	phaseContent = "---\ndesigns: [api, data-model]\n---\n# Phase 1: Backend\n"
+	if err := os.WriteFile(filepath.Join(phasesDir, "1-backend.md"), []byte(phaseContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result = ValidateDesign(root, dir)
+	if !result.Passed {
+		t.Errorf("expected validation to pass once all references exist: %s", result.Message)
+	}
+}
+
+func TestValidateDesignIncludes(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	designsDir := filepath.Join(root, ".foreman", "designs")
+	apiContent := "# API Design\n\nSchema:\n\n<!-- foreman:include api/schema.sql -->\n"
+	if err := os.WriteFile(filepath.Join(designsDir, "api.md"), []byte(apiContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ValidateDesign(root, dir)
+	if result.Passed {
+		t.Fatal("expected validation to fail with a missing include target")
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "designs/api.md") || !strings.Contains(result.Details[0], "api/schema.sql") {
+		t.Errorf("expected details to name the design and the missing file, got %v", result.Details)
+	}
+
+	// Include paths are relative to the project root
+	if err := os.MkdirAll(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "api", "schema.sql"), []byte("CREATE TABLE users (id INT);\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result = ValidateDesign(root, dir)
+	if !result.Passed {
+		t.Errorf("expected validation to pass once the include exists: %s %v", result.Message, result.Details)
+	}
+}
+
+func TestValidateDesignNested(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	designsDir := filepath.Join(root, ".foreman", "designs")
+	if err := os.MkdirAll(filepath.Join(designsDir, "backend"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	apiContent := "# API Design\n\nREST endpoints for the service."
+	if err := os.WriteFile(filepath.Join(designsDir, "backend", "api.md"), []byte(apiContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ValidateDesign(root, dir)
+	if !result.Passed {
+		t.Fatalf("expected designs in subdirectories to count: %s", result.Message)
+	}
+	if !strings.Contains(result.Details[0], "backend/api.md") {
+		t.Errorf("expected details to show the relative path, got %v", result.Details)
+	}
+
+	// The index may only list existing designs
+	index := "order:\n  - backend/api.md\n  - frontend/ui.md\n"
+	if err := os.WriteFile(filepath.Join(designsDir, "index.yaml"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = ValidateDesign(root, dir)
+	if result.Passed || len(result.Details) != 1 || !strings.Contains(result.Details[0], "frontend/ui.md") {
+		t.Errorf("expected index entry for a missing design to fail, got %s %v", result.Message, result.Details)
+	}
+}
+
+func TestValidateDesignPlaceholders(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	path := filepath.Join(root, ".foreman", "designs", "api.md")
+	design := "# API\n\n## Overview\n\n_What this part of the system does\nand why it exists._\n\n## Endpoints\n\nGET /health returns 200.\n\n```\n_not a placeholder_\n```"
+	if err := os.WriteFile(path, []byte(design), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ValidateDesign(root, dir)
+	if result.Passed || len(result.Details) != 2 || result.Details[1] != "designs/api.md: _What this part of the system does and why it exists._" {
+		t.Errorf("expected the italic placeholder to fail the gate, got %s %v", result.Message, result.Details)
+	}
+
+	design = strings.Replace(design, "_What this part of the system does\nand why it exists._", "Serves the _public_ API.", 1)
+	if err := os.WriteFile(path, []byte(design), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := ValidateDesign(root, dir); !result.Passed {
+		t.Errorf("expected a filled-in design to pass: %s %v", result.Message, result.Details)
+	}
+}
+
 func TestValidatePhases(t *testing.T) {
 	root := setupTestProject(t)
 	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
 
 	phasesDir := filepath.Join(root, ".foreman", "phases")
 
 	// Test missing overview.md
-	result := ValidatePhases(root)
+	result := ValidatePhases(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with missing overview.md")
 	}
@@ -174,7 +319,7 @@ func TestValidatePhases(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result = ValidatePhases(root)
+	result = ValidatePhases(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with empty overview.md")
 	}
@@ -195,7 +340,7 @@ Break implementation into manageable phases that can be executed independently.
 		t.Fatal(err)
 	}
 
-	result = ValidatePhases(root)
+	result = ValidatePhases(root, dir)
 	if result.Passed {
 		t.Error("expected validation to fail with no phase files")
 	}
@@ -233,12 +378,94 @@ Break implementation into manageable phases that can be executed independently.
 		t.Fatal(err)
 	}
 
-	result = ValidatePhases(root)
+	result = ValidatePhases(root, dir)
 	if !result.Passed {
 		t.Errorf("expected validation to pass with valid phase files: %s", result.Message)
 	}
 }
 
+func TestValidatePhasesOverviewMismatch(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	phasesDir := filepath.Join(root, ".foreman", "phases")
+	overview := `# Implementation Phases
+
+## Phases
+
+| # | Title | Goal |
+|---|-------|------|
+| 1 | Setup | Project structure and basic CLI |
+| 2 | Core | State management and validation |
+`
+	files := map[string]string{
+		"overview.md": overview,
+		"1-setup.md":  "# Phase 1: Setup",
+		"3-extra.md":  "# Phase 3: Extra",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(phasesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := ValidatePhases(root, dir)
+	if !result.Passed {
+		t.Fatalf("expected a mismatch to warn, not fail: %s", result.Message)
+	}
+	warnings := strings.Join(result.Warnings, "\n")
+	if len(result.Warnings) != 2 || !strings.Contains(warnings, "phase 2 (Core)") || !strings.Contains(warnings, "3-extra.md") {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+
+	if err := os.WriteFile(filepath.Join(phasesDir, "2-core.md"), []byte("# Phase 2: Core"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(phasesDir, "3-extra.md")); err != nil {
+		t.Fatal(err)
+	}
+	if result := ValidatePhases(root, dir); len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings once overview and files agree: %v", result.Warnings)
+	}
+}
+
+func TestValidatePhasesDuplicateNumbers(t *testing.T) {
+	root := setupTestProject(t)
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
+
+	phasesDir := filepath.Join(root, ".foreman", "phases")
+	files := map[string]string{
+		"overview.md":         "# Implementation Phases\n\n1. Setup\n2. Backend\n   1. Auth",
+		"1-setup.md":          "# Phase 1: Setup",
+		"2-backend.md":        "# Phase 2: Backend",
+		"2-backend/1-auth.md": "# Phase 2.1: Auth",
+		"2.1-auth.md":         "# Phase 2.1: Auth",
+	}
+	for name, content := range files {
+		path := filepath.Join(phasesDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := ValidatePhases(root, dir)
+	if result.Passed || !strings.Contains(strings.Join(result.Details, "\n"), "phase 2.1: 2-backend/1-auth, 2.1-auth") {
+		t.Errorf("expected the clashing phase numbers to be reported, got %s %v", result.Message, result.Details)
+	}
+
+	if err := os.Remove(filepath.Join(phasesDir, "2.1-auth.md")); err != nil {
+		t.Fatal(err)
+	}
+	if result := ValidatePhases(root, dir); !result.Passed {
+		t.Errorf("expected unique phase numbers to pass: %s %v", result.Message, result.Details)
+	}
+}
+
 func TestValidateImplementation(t *testing.T) {
 	root := setupTestProject(t)
 	defer os.RemoveAll(root)
@@ -274,11 +501,12 @@ func TestValidateImplementation(t *testing.T) {
 func TestValidateStage(t *testing.T) {
 	root := setupTestProject(t)
 	defer os.RemoveAll(root)
+	dir := filepath.Join(root, ".foreman")
 
 	st := state.NewDefault()
 
 	// Test invalid stage
-	_, err := ValidateStage(root, "invalid", st)
+	_, err := ValidateStage(root, dir, "invalid", st)
 	if err == nil {
 		t.Error("expected error for invalid stage")
 	}
@@ -286,7 +514,7 @@ func TestValidateStage(t *testing.T) {
 	// Test valid stages
 	validStages := []string{"requirements", "design", "phases", "implementation"}
 	for _, stage := range validStages {
-		result, err := ValidateStage(root, stage, st)
+		result, err := ValidateStage(root, dir, stage, st)
 		if err != nil {
 			t.Errorf("unexpected error validating stage %s: %v", stage, err)
 		}
@@ -0,0 +1,388 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Run executes git with args in dir and returns its trimmed stdout.
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsRepo reports whether dir is inside a git work tree.
+func IsRepo(dir string) bool {
+	out, err := Run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// CurrentBranch returns the checked-out branch name ("HEAD" when detached).
+func CurrentBranch(dir string) (string, error) {
+	out, err := Run(dir, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || out == "" {
+		return Run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	}
+	return out, nil
+}
+
+// Head returns the full commit hash of HEAD.
+func Head(dir string) (string, error) {
+	return Run(dir, "rev-parse", "HEAD")
+}
+
+// ShortHash abbreviates a commit hash for display.
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// ListFiles returns tracked and untracked files that are not ignored by .gitignore,
+// relative to dir.
+func ListFiles(dir string) ([]string, error) {
+	out, err := Run(dir, "ls-files", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// splitLines splits git output into non-empty lines.
+func splitLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ChangedFiles returns files with uncommitted changes (staged, unstaged or
+// untracked), relative to dir and limited to it.
+func ChangedFiles(dir string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(out string) {
+		for _, f := range splitLines(out) {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+
+	if _, err := Head(dir); err == nil {
+		out, err := Run(dir, "diff", "--name-only", "--relative", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		add(out)
+	} else {
+		// No commits yet: everything in the index is new
+		out, err := Run(dir, "ls-files", "--cached")
+		if err != nil {
+			return nil, err
+		}
+		add(out)
+	}
+
+	out, err := Run(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	add(out)
+
+	return files, nil
+}
+
+// StagedFiles returns files staged for the next commit, relative to dir and limited to it.
+func StagedFiles(dir string) ([]string, error) {
+	out, err := Run(dir, "diff", "--cached", "--name-only", "--relative")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// RangeFiles returns files changed in a revision range, relative to dir and limited to it.
+func RangeFiles(dir, revRange string) ([]string, error) {
+	out, err := Run(dir, "diff", "--name-only", "--relative", revRange)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// TopLevel returns the root directory of the work tree containing dir.
+func TopLevel(dir string) (string, error) {
+	return Run(dir, "rev-parse", "--show-toplevel")
+}
+
+// HooksDir returns the absolute path of the repository's hooks directory.
+func HooksDir(dir string) (string, error) {
+	out, err := Run(dir, "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// CommitBefore returns the last commit on HEAD made at or before t.
+func CommitBefore(dir string, t time.Time) (string, error) {
+	out, err := Run(dir, "rev-list", "-1", "--before="+t.Format(time.RFC3339), "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", fmt.Errorf("no commit found before %s", t.Format(time.RFC3339))
+	}
+	return out, nil
+}
+
+// Log returns the one-line log of a revision range.
+func Log(dir, revRange string) (string, error) {
+	return Run(dir, "log", "--oneline", "--no-decorate", revRange)
+}
+
+// Diff returns the full diff of a revision range.
+func Diff(dir, revRange string) (string, error) {
+	return Run(dir, "diff", revRange)
+}
+
+// DiffStat returns the diffstat between a commit and the working tree.
+func DiffStat(dir, from string) (string, error) {
+	return Run(dir, "diff", "--stat", from)
+}
+
+// BranchExists reports whether a local branch exists.
+func BranchExists(dir, name string) bool {
+	_, err := Run(dir, "show-ref", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// CheckoutBranch switches to a branch, creating it from HEAD if it doesn't exist.
+func CheckoutBranch(dir, name string) error {
+	if current, err := CurrentBranch(dir); err == nil && current == name {
+		return nil
+	}
+	if BranchExists(dir, name) {
+		_, err := Run(dir, "checkout", "-q", name)
+		return err
+	}
+	_, err := Run(dir, "checkout", "-q", "-b", name)
+	return err
+}
+
+// TagExists reports whether a tag exists.
+func TagExists(dir, name string) bool {
+	_, err := Run(dir, "show-ref", "--verify", "--quiet", "refs/tags/"+name)
+	return err == nil
+}
+
+// TagAnnotated creates an annotated tag at HEAD. An existing tag of the same
+// name is an error; it is never moved.
+func TagAnnotated(dir, name, message string) error {
+	if TagExists(dir, name) {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+	_, err := Run(dir, "tag", "-a", name, "-m", message)
+	return err
+}
+
+// FreeTagName returns name if no such tag exists, otherwise the first of
+// name-2, name-3, ... that is still free.
+func FreeTagName(dir, name string) string {
+	candidate := name
+	for n := 2; TagExists(dir, candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d", name, n)
+	}
+	return candidate
+}
+
+// HasChanges reports whether the given paths have uncommitted or untracked changes.
+func HasChanges(dir string, paths ...string) (bool, error) {
+	args := append([]string{"status", "--porcelain", "--"}, paths...)
+	out, err := Run(dir, args...)
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// CommitPaths stages and commits only the given paths, leaving other staged changes alone.
+func CommitPaths(dir, message string, paths ...string) error {
+	if _, err := Run(dir, append([]string{"add", "--"}, paths...)...); err != nil {
+		return err
+	}
+	_, err := Run(dir, append([]string{"commit", "-q", "-m", message, "--"}, paths...)...)
+	return err
+}
+
+// Trailer is a "Key: value" line at the end of a commit message.
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// FormatMessage builds a commit message from a subject line and trailers.
+func FormatMessage(subject string, trailers []Trailer) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	if len(trailers) > 0 {
+		b.WriteString("\n\n")
+		for _, t := range trailers {
+			b.WriteString(fmt.Sprintf("%s: %s\n", t.Key, t.Value))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// StagedContent returns the staged (index) content of a path relative to dir.
+func StagedContent(dir, path string) ([]byte, error) {
+	out, err := Run(dir, "show", ":./"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// FileAt returns the content of a file at a revision, or nil if the file
+// does not exist there.
+func FileAt(dir, rev, path string) []byte {
+	out, err := Run(dir, "show", rev+":./"+path)
+	if err != nil {
+		return nil
+	}
+	return []byte(out)
+}
+
+// AddTrailer adds a trailer to the commit message file at path, unless the
+// message already has a trailer with the same key.
+func AddTrailer(dir, path string, t Trailer) error {
+	_, err := Run(dir, "interpret-trailers", "--in-place", "--if-exists", "doNothing", "--trailer", t.Key+": "+t.Value, path)
+	return err
+}
+
+// ParseTrailers returns the trailers in the last paragraph of a commit message.
+// Comment lines (starting with '#') are ignored, as git strips them.
+func ParseTrailers(message string) []Trailer {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	// Find the last non-empty paragraph
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	// A lone subject line is not a trailer block
+	if start == 0 {
+		return nil
+	}
+
+	var trailers []Trailer
+	for _, line := range lines[start:end] {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil
+		}
+		trailers = append(trailers, Trailer{Key: key, Value: strings.TrimSpace(value)})
+	}
+	return trailers
+}
+
+// Commit is a commit's hash, first parent, author time and full message.
+type Commit struct {
+	Hash    string
+	Parent  string
+	Time    time.Time
+	Message string
+}
+
+// Commits returns the commits reachable from a revision (or in a range), oldest first.
+func Commits(dir, rev string) ([]Commit, error) {
+	// Fields are NUL separated and records are terminated by a record separator
+	out, err := Run(dir, "log", "--reverse", "--format=%H%x00%P%x00%at%x00%B%x1e", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Message: strings.TrimSpace(fields[3])}
+		if parents := strings.Fields(fields[1]); len(parents) > 0 {
+			c.Parent = parents[0]
+		}
+		var unix int64
+		fmt.Sscanf(fields[2], "%d", &unix)
+		c.Time = time.Unix(unix, 0)
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// Subject returns the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// AddedIn returns the oldest commit reachable from HEAD that added path, or
+// "" when path was never committed.
+func AddedIn(dir, path string) string {
+	out, err := Run(dir, "log", "--diff-filter=A", "--format=%H", "HEAD", "--", path)
+	if err != nil {
+		return ""
+	}
+	lines := splitLines(out)
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[len(lines)-1]
+}
+
+// HasParent reports whether a commit has a parent.
+func HasParent(dir, commit string) bool {
+	_, err := Run(dir, "rev-parse", "--verify", "-q", commit+"^")
+	return err == nil
+}
+
+// IsAncestor reports whether commit a is an ancestor of (or equal to) commit b.
+func IsAncestor(dir, a, b string) bool {
+	_, err := Run(dir, "merge-base", "--is-ancestor", a, b)
+	return err == nil
+}
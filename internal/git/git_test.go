@@ -0,0 +1,245 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupTestRepo(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "foreman-git-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Run(dir, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	for _, kv := range [][]string{{"user.name", "foreman"}, {"user.email", "foreman@example.com"}} {
+		if _, err := Run(dir, "config", kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func commitFile(t *testing.T, dir, name, content string) string {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(dir, "add", name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(dir, "commit", "-q", "-m", "add "+name); err != nil {
+		t.Fatal(err)
+	}
+	head, err := Head(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
+func TestRepoBasics(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	if !IsRepo(dir) {
+		t.Fatal("expected directory to be a git repository")
+	}
+
+	head := commitFile(t, dir, "main.go", "package main\n")
+	if len(head) != 40 {
+		t.Errorf("expected full commit hash, got %q", head)
+	}
+	if ShortHash(head) != head[:7] {
+		t.Errorf("expected short hash %s, got %s", head[:7], ShortHash(head))
+	}
+
+	branch, err := CurrentBranch(dir)
+	if err != nil || branch == "" {
+		t.Errorf("expected a branch name, got %q (%v)", branch, err)
+	}
+}
+
+func TestListFilesRespectsGitignore(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	commitFile(t, dir, ".gitignore", "build/\n")
+	if err := os.MkdirAll(filepath.Join(dir, "build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "build", "out.bin"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untracked.go"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ListFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	for _, f := range files {
+		got[f] = true
+	}
+	if !got["untracked.go"] || !got[".gitignore"] {
+		t.Errorf("expected tracked and untracked files, got %v", files)
+	}
+	if got["build/out.bin"] {
+		t.Error("expected ignored file to be excluded")
+	}
+}
+
+func TestDiffStatSinceCommit(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	first := commitFile(t, dir, "a.go", "package a\n")
+
+	commit, err := CommitBefore(dir, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit != first {
+		t.Errorf("expected %s, got %s", first, commit)
+	}
+
+	commitFile(t, dir, "b.go", "package b\n")
+
+	stat, err := DiffStat(dir, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat == "" {
+		t.Error("expected a non-empty diffstat")
+	}
+}
+
+func TestCheckoutTagAndCommit(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	commitFile(t, dir, "README.md", "# repo\n")
+
+	if err := CheckoutBranch(dir, "phase/1-setup"); err != nil {
+		t.Fatal(err)
+	}
+	if branch, _ := CurrentBranch(dir); branch != "phase/1-setup" {
+		t.Errorf("expected branch phase/1-setup, got %s", branch)
+	}
+	if !BranchExists(dir, "phase/1-setup") {
+		t.Error("expected branch to exist")
+	}
+
+	// Only the given paths are committed
+	if err := os.MkdirAll(filepath.Join(dir, ".foreman"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".foreman", "state.yaml"), []byte("current_stage: design\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.go"), []byte("package other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	message := FormatMessage("foreman: phase 1-setup → done", []Trailer{{Key: "Foreman-Phase", Value: "1-setup"}})
+	if err := CommitPaths(dir, message, ".foreman"); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := HasChanges(dir, ".foreman"); changed {
+		t.Error("expected .foreman to be committed")
+	}
+	if changed, _ := HasChanges(dir, "other.go"); !changed {
+		t.Error("expected other.go to remain uncommitted")
+	}
+
+	body, err := Run(dir, "log", "-1", "--format=%B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "foreman: phase 1-setup → done\n\nForeman-Phase: 1-setup" {
+		t.Errorf("unexpected commit message: %q", body)
+	}
+
+	if err := TagAnnotated(dir, "foreman/design-approved", "design approved"); err != nil {
+		t.Fatal(err)
+	}
+	// Re-approving doesn't move the existing tag
+	if err := TagAnnotated(dir, "foreman/design-approved", "design approved again"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an existing tag to be reported, got %v", err)
+	}
+	if got := FreeTagName(dir, "foreman/design-approved"); got != "foreman/design-approved-2" {
+		t.Errorf("expected the next free tag to be suffixed, got %s", got)
+	}
+	if got := FreeTagName(dir, "foreman/phases-approved"); got != "foreman/phases-approved" {
+		t.Errorf("expected an unused tag name to be returned unchanged, got %s", got)
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	msg := FormatMessage("Add login endpoint", []Trailer{{Key: "Phase", Value: "2-backend"}, {Key: "Foreman-Status", Value: "done"}})
+	trailers := ParseTrailers(msg + "\n# Please enter the commit message\n")
+	if len(trailers) != 2 || trailers[0].Key != "Phase" || trailers[0].Value != "2-backend" || trailers[1].Value != "done" {
+		t.Errorf("unexpected trailers: %+v", trailers)
+	}
+
+	if got := ParseTrailers("Phase: 2-backend"); got != nil {
+		t.Errorf("a lone subject line should not be parsed as trailers, got %+v", got)
+	}
+	if got := ParseTrailers("Subject\n\nThis is prose: not a trailer block"); got != nil {
+		t.Errorf("prose should not be parsed as trailers, got %+v", got)
+	}
+}
+
+func TestCommits(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	first := commitFile(t, dir, "a.txt", "a\n")
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(dir, "add", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	msg := FormatMessage("Add b", []Trailer{{Key: "Foreman-Phase", Value: "1-setup"}})
+	if _, err := Run(dir, "commit", "-q", "-m", msg); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Head(dir)
+
+	commits, err := Commits(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Hash != first || commits[1].Hash != second {
+		t.Fatalf("expected commits oldest first, got %+v", commits)
+	}
+	if commits[1].Parent != first || commits[0].Parent != "" {
+		t.Errorf("unexpected parents: %q, %q", commits[0].Parent, commits[1].Parent)
+	}
+	if commits[1].Subject() != "Add b" || commits[1].Message != msg {
+		t.Errorf("unexpected message: %q", commits[1].Message)
+	}
+
+	since, err := Commits(dir, first+"..HEAD")
+	if err != nil || len(since) != 1 {
+		t.Errorf("expected one commit in range, got %v (%v)", since, err)
+	}
+	if !IsAncestor(dir, first, second) || IsAncestor(dir, second, first) {
+		t.Error("unexpected ancestry")
+	}
+	if AddedIn(dir, "b.txt") != second || AddedIn(dir, "c.txt") != "" {
+		t.Errorf("expected b.txt to be added in %s, got %q", second, AddedIn(dir, "b.txt"))
+	}
+	if !HasParent(dir, second) || HasParent(dir, first) {
+		t.Error("expected only the second commit to have a parent")
+	}
+}
@@ -0,0 +1,201 @@
+package gitsync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/hooks"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// StatusTrailer is the commit trailer carrying a phase's new status.
+const StatusTrailer = "Foreman-Status"
+
+// AppliedTrailer marks the commits foreman makes for its own state changes,
+// which were applied when they were made.
+const AppliedTrailer = "Foreman-Applied"
+
+// Transition is a phase status change read from a commit's trailers.
+type Transition struct {
+	Commit git.Commit
+	Phase  string
+	Status string
+}
+
+// Skipped is a transition that was not applied, with the reason.
+type Skipped struct {
+	Transition Transition
+	Reason     string
+}
+
+// Result summarizes a sync.
+type Result struct {
+	Range   string       // revision range that was scanned
+	Scanned int          // number of commits scanned
+	Applied []Transition // transitions applied to the state
+	Skipped []Skipped    // transitions rejected by validation
+	Reset   bool         // the stored cursor was no longer on HEAD's history
+}
+
+// Transitions extracts the phase transitions of track from commit trailers.
+// A phase trailer ("Foreman-Phase" or "Phase") without a status means the
+// phase is being worked on, so it is reported as "in-progress". Commits
+// carrying the AppliedTrailer are skipped, and so are commits whose
+// hooks.TrackTrailer names another track (without one, a commit belongs to
+// the main track).
+func Transitions(commits []git.Commit, track string) []Transition {
+	var transitions []Transition
+	for _, c := range commits {
+		trailers := git.ParseTrailers(c.Message)
+		if applied(trailers) || commitTrack(trailers) != track {
+			continue
+		}
+
+		var phases []string
+		status := ""
+		for _, t := range trailers {
+			if strings.EqualFold(t.Key, StatusTrailer) {
+				status = t.Value
+				continue
+			}
+			for _, key := range hooks.PhaseTrailers {
+				if strings.EqualFold(t.Key, key) {
+					phases = append(phases, t.Value)
+				}
+			}
+		}
+
+		for _, phase := range phases {
+			transitions = append(transitions, Transition{Commit: c, Phase: phase, Status: status})
+		}
+	}
+	return transitions
+}
+
+// applied reports whether the trailers mark a commit foreman made for its own state change.
+func applied(trailers []git.Trailer) bool {
+	for _, t := range trailers {
+		if strings.EqualFold(t.Key, AppliedTrailer) && strings.EqualFold(t.Value, "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// commitTrack returns the track named by the trailers, or the main track.
+func commitTrack(trailers []git.Trailer) string {
+	for _, t := range trailers {
+		if strings.EqualFold(t.Key, hooks.TrackTrailer) {
+			return t.Value
+		}
+	}
+	return config.MainTrack
+}
+
+// Apply validates a transition with the rules of 'foreman phase' and applies it.
+// It reports false when the transition doesn't change anything.
+func Apply(st *state.State, t Transition) (bool, error) {
+	phase := st.GetPhase(t.Phase)
+	if phase == nil {
+		return false, fmt.Errorf("phase %s not found", t.Phase)
+	}
+
+	status := t.Status
+	if status == "" {
+		// Work on a planned phase starts it; later commits don't change it
+		if phase.Status != "planned" {
+			return false, nil
+		}
+		status = "in-progress"
+	}
+	if phase.Status == status {
+		return false, nil
+	}
+
+	if err := st.SetPhaseStatus(t.Phase, status); err != nil {
+		return false, err
+	}
+
+	// The commit's own changes belong to the phase: it starts at the parent
+	// and ends with the commit itself
+	switch status {
+	case "in-progress":
+		start := t.Commit.Parent
+		if start == "" {
+			start = t.Commit.Hash
+		}
+		phase.RecordCommit(status, start)
+	case "done":
+		phase.RecordCommit(status, t.Commit.Hash)
+		completedAt := t.Commit.Time
+		phase.CompletedAt = &completedAt
+	default:
+		phase.RecordCommit(status, "")
+	}
+	return true, nil
+}
+
+// Sync scans the commits since the stored cursor, applies the phase
+// transitions of track found in their trailers, and advances the cursor to
+// HEAD. st is the track's state, which keeps the track's own cursor. Without
+// a usable cursor the scan starts at the commit that added the track's
+// state.yaml, or covers all of HEAD when state.yaml was never committed.
+// Running it again without new commits changes nothing.
+func Sync(root, track string, st *state.State) (*Result, error) {
+	if !git.IsRepo(root) {
+		return nil, fmt.Errorf("not a git repository: %s", root)
+	}
+
+	result := &Result{}
+	head, err := git.Head(root)
+	if err != nil {
+		// No commits yet
+		return result, nil
+	}
+	if st.GitCursor == head {
+		result.Range = git.ShortHash(head) + "..HEAD"
+		return result, nil
+	}
+
+	rev := "HEAD"
+	result.Range = "HEAD"
+	if st.GitCursor != "" {
+		if git.IsAncestor(root, st.GitCursor, head) {
+			rev = st.GitCursor + "..HEAD"
+			result.Range = git.ShortHash(st.GitCursor) + "..HEAD"
+		} else {
+			// History was rewritten or another branch is checked out
+			result.Reset = true
+		}
+	}
+	if rev == "HEAD" {
+		// Commits from before the track's state.yaml was added can't be about it
+		added := git.AddedIn(root, hooks.StateFile(root, config.TrackDir(root, track)))
+		if added != "" && git.HasParent(root, added) {
+			rev = added + "^..HEAD"
+			result.Range = git.ShortHash(added) + "^..HEAD"
+		}
+	}
+
+	commits, err := git.Commits(root, rev)
+	if err != nil {
+		return nil, err
+	}
+	result.Scanned = len(commits)
+
+	for _, t := range Transitions(commits, track) {
+		applied, err := Apply(st, t)
+		if err != nil {
+			result.Skipped = append(result.Skipped, Skipped{Transition: t, Reason: err.Error()})
+			continue
+		}
+		if applied {
+			result.Applied = append(result.Applied, t)
+		}
+	}
+
+	st.GitCursor = head
+	return result, nil
+}
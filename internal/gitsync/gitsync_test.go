@@ -0,0 +1,167 @@
+package gitsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+func TestTransitions(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "a", Message: "Set up repo\n\nForeman-Phase: 1-setup"},
+		{Hash: "b", Message: "Finish setup\n\nForeman-Phase: 1-setup\nForeman-Status: done"},
+		{Hash: "c", Message: "foreman: phase 2-backend → in-progress\n\nForeman-Phase: 2-backend\nForeman-Status: in-progress\nForeman-Applied: true"},
+		{Hash: "d", Message: "Unrelated change"},
+		{Hash: "e", Message: "foreman: wire up sync\n\nForeman-Phase: 2-backend"},
+		{Hash: "f", Message: "Set up billing\n\nForeman-Phase: 1-setup\nForeman-Track: billing"},
+	}
+
+	transitions := Transitions(commits, config.MainTrack)
+	if len(transitions) != 3 {
+		t.Fatalf("expected 2 transitions (foreman's own commits skipped), got %+v", transitions)
+	}
+	if transitions[0].Phase != "1-setup" || transitions[0].Status != "" {
+		t.Errorf("unexpected first transition: %+v", transitions[0])
+	}
+	if transitions[1].Commit.Hash != "b" || transitions[1].Status != "done" {
+		t.Errorf("unexpected second transition: %+v", transitions[1])
+	}
+	if transitions[2].Commit.Hash != "e" {
+		t.Errorf("expected a user commit with a foreman: subject to be read, got %+v", transitions[2])
+	}
+
+	// Each track only gets the commits naming it
+	transitions = Transitions(commits, "billing")
+	if len(transitions) != 1 || transitions[0].Commit.Hash != "f" {
+		t.Errorf("expected only the billing commit, got %+v", transitions)
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir, err := os.MkdirTemp("", "foreman-gitsync-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	commit := func(message string) string {
+		args := []string{"-c", "user.name=foreman", "-c", "user.email=foreman@example.com", "commit", "-q", "--allow-empty", "-m", message}
+		if _, err := git.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+		head, _ := git.Head(dir)
+		return head
+	}
+
+	st := state.NewDefault()
+	st.AddPhase("1-setup")
+	st.AddPhase("2-backend")
+
+	base := commit("Initial commit")
+	commit("Scaffold\n\nForeman-Phase: 1-setup")
+	done := commit("Wire CI\n\nForeman-Phase: 1-setup\nForeman-Status: done")
+	commit("Start API\n\nForeman-Phase: 9-missing")
+
+	result, err := Sync(dir, config.MainTrack, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 4 || len(result.Applied) != 2 || len(result.Skipped) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	phase := st.GetPhase("1-setup")
+	if phase.Status != "done" || phase.StartCommit != base || phase.EndCommit != done {
+		t.Errorf("unexpected phase after sync: %+v", phase)
+	}
+
+	// Syncing again is a no-op
+	result, err = Sync(dir, config.MainTrack, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 0 || len(result.Applied) != 0 {
+		t.Errorf("expected second sync to do nothing, got %+v", result)
+	}
+
+	// Only new commits are scanned
+	commit("Add handlers\n\nPhase: 2-backend")
+	result, err = Sync(dir, config.MainTrack, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 1 || st.GetPhase("2-backend").Status != "in-progress" {
+		t.Errorf("expected 2-backend in progress after one new commit, got %+v", result)
+	}
+
+	// A track with a phase of the same name keeps its own cursor and commits
+	billing := state.NewDefault()
+	billing.AddPhase("1-setup")
+	commit("Invoice model\n\nForeman-Phase: 1-setup\nForeman-Track: billing")
+	result, err = Sync(dir, "billing", billing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 6 || len(result.Applied) != 1 || billing.GetPhase("1-setup").Status != "in-progress" {
+		t.Errorf("expected only the billing commit to be applied, got %+v", result)
+	}
+	result, err = Sync(dir, config.MainTrack, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 1 || len(result.Applied) != 0 || st.GetPhase("1-setup").Status != "done" {
+		t.Errorf("expected the billing commit to leave the main track alone, got %+v", result)
+	}
+}
+
+// TestSyncStartsAtState tests that the first sync skips the commits made
+// before the track's state.yaml was added.
+func TestSyncStartsAtState(t *testing.T) {
+	dir, err := os.MkdirTemp("", "foreman-gitsync-start-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	commit := func(message string) {
+		args := []string{"-c", "user.name=foreman", "-c", "user.email=foreman@example.com", "commit", "-q", "--allow-empty", "-m", message}
+		if _, err := git.Run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit("Initial commit")
+	commit("Old project\n\nForeman-Phase: 1-setup\nForeman-Status: done")
+
+	st := state.NewDefault()
+	st.AddPhase("1-setup")
+	foremanDir := config.TrackDir(dir, config.MainTrack)
+	if err := os.MkdirAll(foremanDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(foremanDir, st); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.Run(dir, "add", filepath.Join(".foreman", "state.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	commit("Start foreman")
+	commit("Scaffold\n\nForeman-Phase: 1-setup")
+
+	result, err := Sync(dir, config.MainTrack, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 2 || len(result.Applied) != 1 || st.GetPhase("1-setup").Status != "in-progress" {
+		t.Errorf("expected only the commits since state.yaml was added to be applied, got %+v", result)
+	}
+}
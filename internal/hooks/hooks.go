@@ -0,0 +1,285 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/scope"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// Marker identifies hook scripts written by foreman.
+const Marker = "# Installed by foreman"
+
+// DisableEnv is the environment variable that turns the hooks off when set to "off", "0" or "false".
+const DisableEnv = "FOREMAN_HOOKS"
+
+// Names lists the git hooks foreman installs.
+var Names = []string{"pre-commit", "commit-msg"}
+
+// PhaseTrailers are the commit message trailers accepted as naming the phase a commit belongs to.
+var PhaseTrailers = []string{"Phase", "Foreman-Phase"}
+
+// TrackTrailer is the commit message trailer naming the track of the phases
+// in the phase trailers. Commits without one belong to the main track.
+const TrackTrailer = "Foreman-Track"
+
+// Disabled reports whether the hooks were turned off through the environment.
+func Disabled() bool {
+	switch strings.ToLower(os.Getenv(DisableEnv)) {
+	case "off", "0", "false":
+		return true
+	}
+	return false
+}
+
+// Script returns the hook script for name. rel is the project root relative to
+// the top of the git work tree.
+func Script(name, rel string) string {
+	setup, args := "", name
+	if name == "commit-msg" {
+		// git passes the message file relative to the top of the work tree,
+		// so it is made absolute before changing to the project root
+		setup = `msg="$(cd "$(dirname "$1")" && pwd)/$(basename "$1")"` + "\n"
+		args += ` "$msg"`
+	}
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# Opt out: set %s=off, commit with 'git commit --no-verify', or run 'foreman hooks uninstall'.
+command -v foreman >/dev/null 2>&1 || exit 0
+%scd "$(git rev-parse --show-toplevel)/%s" || exit 1
+exec foreman hooks run %s
+`, Marker, DisableEnv, setup, filepath.ToSlash(rel), args)
+}
+
+// hookPaths returns the hooks directory and the project root relative to the work tree.
+func hookPaths(root string) (string, string, error) {
+	if !git.IsRepo(root) {
+		return "", "", fmt.Errorf("not a git repository: %s", root)
+	}
+	hooksDir, err := git.HooksDir(root)
+	if err != nil {
+		return "", "", err
+	}
+	top, err := git.TopLevel(root)
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := filepath.Rel(top, root)
+	if err != nil {
+		return "", "", err
+	}
+	return hooksDir, rel, nil
+}
+
+// Install writes the named hooks and returns their paths. Existing hooks not
+// written by foreman are left alone unless force is set.
+func Install(root string, names []string, force bool) ([]string, error) {
+	hooksDir, rel, err := hookPaths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every hook before writing any, so a conflict leaves nothing half-installed
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), Marker) && !force {
+			return nil, fmt.Errorf("%s already exists and was not written by foreman (use --force to replace it)", path)
+		}
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		if err := os.WriteFile(path, []byte(Script(name, rel)), 0755); err != nil {
+			return paths, fmt.Errorf("failed to write hook: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Uninstall removes the hooks written by foreman and returns their paths.
+func Uninstall(root string) ([]string, error) {
+	hooksDir, _, err := hookPaths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, name := range Names {
+		path := filepath.Join(hooksDir, name)
+		existing, err := os.ReadFile(path)
+		if err != nil || !strings.Contains(string(existing), Marker) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// codeFiles returns the files outside .foreman/.
+func codeFiles(files []string) []string {
+	var code []string
+	for _, f := range files {
+		if !strings.HasPrefix(f, project.ForemanDir+"/") {
+			code = append(code, f)
+		}
+	}
+	return code
+}
+
+// inProgress returns the names of phases that are in progress.
+func inProgress(st *state.State) []string {
+	var names []string
+	for _, phase := range st.Phases {
+		if phase.Status == "in-progress" {
+			names = append(names, phase.Name)
+		}
+	}
+	return names
+}
+
+// StateFile returns the path of the state.yaml in the track directory dir
+// relative to the project root, as git reports staged files.
+func StateFile(root, dir string) string {
+	rel, err := filepath.Rel(root, state.StatePath(dir))
+	if err != nil {
+		return project.ForemanDir + "/state.yaml"
+	}
+	return filepath.ToSlash(rel)
+}
+
+// CheckPreCommit returns the policy violations of the staged files for the
+// track in dir (see config.TrackDir): code changes before the implementation
+// stage, an invalid staged state.yaml (or an invalid transition to it from
+// HEAD), and files outside the in-progress phases' scope. indexState and headState are the content of
+// state.yaml in the index and at HEAD (nil where it is not tracked). The
+// checks use the index state, which is what gets committed; st (the working
+// tree state) is only used when state.yaml is not in the index.
+func CheckPreCommit(root, dir string, st *state.State, staged []string, indexState, headState []byte) []string {
+	var problems []string
+
+	stateFile := StateFile(root, dir)
+	if indexState != nil {
+		next, err := state.Parse(indexState)
+		isStaged := slices.Contains(staged, stateFile)
+		switch {
+		case err != nil:
+			if isStaged {
+				problems = append(problems, fmt.Sprintf("staged %s is invalid: %v", stateFile, err))
+			}
+		case isStaged:
+			for _, err := range next.Validate() {
+				problems = append(problems, fmt.Sprintf("staged %s is invalid: %v", stateFile, err))
+			}
+			if prev, err := state.Parse(headState); headState != nil && err == nil {
+				for _, err := range next.ValidateTransition(prev) {
+					problems = append(problems, fmt.Sprintf("staged %s: %v", stateFile, err))
+				}
+			}
+			st = next
+		default:
+			st = next
+		}
+	}
+
+	code := codeFiles(staged)
+	if len(code) > 0 && st.GetStageIndexInWorkflow(st.CurrentStage) < st.GetStageIndexInWorkflow("implementation") {
+		problems = append(problems, fmt.Sprintf("code changes are blocked during the %s stage (%d files staged outside %s/); approve the earlier gates first", st.CurrentStage, len(code), project.ForemanDir))
+	}
+
+	// Phase scope (phases without a scope are unrestricted)
+	var patterns []string
+	for _, name := range inProgress(st) {
+		meta, err := project.ReadPhaseMeta(dir, name)
+		if err != nil {
+			problems = append(problems, err.Error())
+			return problems
+		}
+		if len(meta.Scope) == 0 {
+			return problems
+		}
+		patterns = append(patterns, meta.Scope...)
+	}
+	if outside := scope.Outside(patterns, staged); len(outside) > 0 {
+		problems = append(problems, fmt.Sprintf("files outside the in-progress phase scope: %s", strings.Join(outside, ", ")))
+	}
+
+	return problems
+}
+
+// CheckCommitMsg returns the policy violations of a commit message: commits
+// changing code must name an in-progress phase in a "Phase: <name>" trailer.
+// Projects without phases (quick mode) and commits touching only .foreman/ are exempt.
+func CheckCommitMsg(st *state.State, message string, staged []string) []string {
+	if len(codeFiles(staged)) == 0 || len(st.Phases) == 0 {
+		return nil
+	}
+
+	active := inProgress(st)
+	if len(active) == 0 {
+		return []string{"no phase is in progress; start one with 'foreman phase <name> in-progress'"}
+	}
+
+	named := phaseNames(message)
+	if len(named) == 0 {
+		return []string{fmt.Sprintf("missing 'Phase: <name>' trailer (in progress: %s)", strings.Join(active, ", "))}
+	}
+
+	var problems []string
+	for _, name := range named {
+		phase := st.GetPhase(name)
+		if phase == nil || phase.Status != "in-progress" {
+			problems = append(problems, fmt.Sprintf("phase %s named in the trailer is not in progress (in progress: %s)", name, strings.Join(active, ", ")))
+		}
+	}
+	return problems
+}
+
+// phaseNames returns the phases named in the phase trailers of a commit message.
+func phaseNames(message string) []string {
+	var names []string
+	for _, t := range git.ParseTrailers(message) {
+		for _, key := range PhaseTrailers {
+			if strings.EqualFold(t.Key, key) {
+				names = append(names, t.Value)
+			}
+		}
+	}
+	return names
+}
+
+// AddTrackTrailer adds a TrackTrailer naming track to the commit message file
+// at path when the message names a phase, so that 'foreman sync --git'
+// applies the commit to that track. Messages on the main track are left alone.
+func AddTrackTrailer(root, path, track string) error {
+	if track == config.MainTrack {
+		return nil
+	}
+	message, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(phaseNames(string(message))) == 0 {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	return git.AddTrailer(root, abs, git.Trailer{Key: TrackTrailer, Value: track})
+}
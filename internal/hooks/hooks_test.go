@@ -0,0 +1,228 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/git"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+func TestCheckPreCommitStage(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".foreman")
+	st := state.NewDefault()
+
+	// Planning documents can be committed at any stage
+	if problems := CheckPreCommit(root, dir, st, []string{".foreman/requirements.md"}, nil, nil); len(problems) != 0 {
+		t.Errorf("expected .foreman changes to be allowed, got %v", problems)
+	}
+
+	problems := CheckPreCommit(root, dir, st, []string{"main.go"}, nil, nil)
+	if len(problems) != 1 || !strings.Contains(problems[0], "requirements stage") {
+		t.Errorf("expected code commit to be blocked during requirements, got %v", problems)
+	}
+
+	if problems := CheckPreCommit(root, dir, state.NewMinimalMode("fix"), []string{"main.go"}, nil, nil); len(problems) != 0 {
+		t.Errorf("expected code commit to be allowed during implementation, got %v", problems)
+	}
+}
+
+func TestCheckPreCommitState(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".foreman")
+	st := state.NewDefault()
+	files := []string{".foreman/state.yaml"}
+
+	valid, err := os.ReadFile(writeState(t, dir, st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := CheckPreCommit(root, dir, st, files, valid, nil); len(problems) != 0 {
+		t.Errorf("expected valid state to be allowed, got %v", problems)
+	}
+
+	edited := strings.Replace(string(valid), "current_stage: requirements", "current_stage: implementation", 1)
+	problems := CheckPreCommit(root, dir, st, files, []byte(edited), nil)
+	if len(problems) == 0 {
+		t.Error("expected hand-edited stage jump to be rejected")
+	}
+
+	if problems := CheckPreCommit(root, dir, st, files, []byte("current_stage: ["), nil); len(problems) != 1 {
+		t.Errorf("expected malformed state to be rejected, got %v", problems)
+	}
+}
+
+func TestCheckPreCommitIndexState(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, ".foreman")
+	committed, err := os.ReadFile(writeState(t, dir, state.NewDefault()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unstaged edit of the working tree state doesn't unblock code commits
+	edited := state.NewMinimalMode("fix")
+	problems := CheckPreCommit(root, dir, edited, []string{"main.go"}, committed, committed)
+	if len(problems) != 1 || !strings.Contains(problems[0], "requirements stage") {
+		t.Errorf("expected the index state to block code commits, got %v", problems)
+	}
+
+	// Staged approvals are checked against HEAD
+	approved := state.NewDefault()
+	if err := approved.ApproveGate("requirements", "human"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := os.ReadFile(writeState(t, dir, approved))
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved.Gates["requirements"].ApprovedBy = "auto"
+	rewritten, err := os.ReadFile(writeState(t, dir, approved))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{".foreman/state.yaml"}
+	if problems := CheckPreCommit(root, dir, approved, files, head, head); len(problems) != 0 {
+		t.Errorf("expected an unchanged state to be allowed, got %v", problems)
+	}
+	problems = CheckPreCommit(root, dir, approved, files, rewritten, head)
+	if len(problems) != 1 || !strings.Contains(problems[0], "approval of gate requirements was edited") {
+		t.Errorf("expected an edited approval to be rejected, got %v", problems)
+	}
+}
+
+func TestCheckCommitMsg(t *testing.T) {
+	st := state.NewMinimalMode("fix")
+	code := []string{"main.go"}
+
+	// No phases (quick mode): no trailer needed
+	if problems := CheckCommitMsg(st, "Fix bug", code); len(problems) != 0 {
+		t.Errorf("expected quick mode commits to be exempt, got %v", problems)
+	}
+
+	st.AddPhase("1-setup")
+	st.AddPhase("2-backend")
+	if problems := CheckCommitMsg(st, "Fix bug", code); len(problems) != 1 || !strings.Contains(problems[0], "no phase is in progress") {
+		t.Errorf("expected no-phase problem, got %v", problems)
+	}
+
+	if err := st.SetPhaseStatus("2-backend", "in-progress"); err != nil {
+		t.Fatal(err)
+	}
+	if problems := CheckCommitMsg(st, "Add API", code); len(problems) != 1 || !strings.Contains(problems[0], "missing 'Phase") {
+		t.Errorf("expected missing trailer problem, got %v", problems)
+	}
+	if problems := CheckCommitMsg(st, "Add API\n\nPhase: 1-setup", code); len(problems) != 1 {
+		t.Errorf("expected trailer naming a planned phase to be rejected, got %v", problems)
+	}
+	if problems := CheckCommitMsg(st, "Add API\n\nPhase: 2-backend", code); len(problems) != 0 {
+		t.Errorf("expected matching trailer to be accepted, got %v", problems)
+	}
+	if problems := CheckCommitMsg(st, "Update plan", []string{".foreman/phases/2-backend.md"}); len(problems) != 0 {
+		t.Errorf("expected .foreman-only commits to be exempt, got %v", problems)
+	}
+}
+
+// TestInstallSubdirectory tests that the installed hooks of a project below
+// the top of the work tree run in the project and can read the commit message.
+func TestInstallSubdirectory(t *testing.T) {
+	top := t.TempDir()
+	if _, err := git.Run(top, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	root := filepath.Join(top, "svc")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(root, Names, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in foreman records where it runs and the message it is given
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "hooks.log")
+	fake := "#!/bin/sh\npwd >> '" + log + "'\nif [ -n \"$4\" ]; then cat \"$4\" >> '" + log + "' || exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(bin, "foreman"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if _, err := git.Run(top, "-c", "user.name=foreman", "-c", "user.email=foreman@example.com", "commit", "-q", "--allow-empty", "-m", "Add API"); err != nil {
+		t.Fatalf("expected the hooks to pass, got %v", err)
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != want || lines[1] != want || lines[2] != "Add API" {
+		t.Errorf("expected both hooks to run in %s and commit-msg to read the message, got %q", want, lines)
+	}
+}
+
+// TestAddTrackTrailer tests that commits naming a phase on a named track get
+// a Foreman-Track trailer, and that other messages are left alone.
+func TestAddTrackTrailer(t *testing.T) {
+	root := t.TempDir()
+	if _, err := git.Run(root, "init", "-q"); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	path := filepath.Join(root, "COMMIT_EDITMSG")
+	write := func(message string) {
+		if err := os.WriteFile(path, []byte(message), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	write("Add invoices\n\nPhase: 1-setup\n")
+	if err := AddTrackTrailer(root, path, "billing"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); !strings.HasSuffix(got, "Phase: 1-setup\nForeman-Track: billing\n") {
+		t.Errorf("expected a track trailer after the phase trailer, got %q", got)
+	}
+	if err := AddTrackTrailer(root, path, "billing"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); strings.Count(got, TrackTrailer) != 1 {
+		t.Errorf("expected the trailer to be added once, got %q", got)
+	}
+
+	for _, c := range []struct{ message, track string }{
+		{"Add invoices\n\nPhase: 1-setup\n", config.MainTrack},
+		{"Fix typo\n", "billing"},
+	} {
+		write(c.message)
+		if err := AddTrackTrailer(root, path, c.track); err != nil {
+			t.Fatal(err)
+		}
+		if got := read(); got != c.message {
+			t.Errorf("expected %q to be left alone on track %s, got %q", c.message, c.track, got)
+		}
+	}
+}
+
+func writeState(t *testing.T, dir string, st *state.State) string {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	return state.StatePath(dir)
+}
@@ -0,0 +1,49 @@
+package project
+
+import (
+	"slices"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// UpdateConfig applies edit to config.yaml in the track directory dir and saves it. Only
+// the project layer is edited, so global, env and flag values are never
+// written into the project. When the edit changes the workflow or the
+// auto-advance threshold, state.yaml is updated to match; the returned
+// bool reports whether it was.
+func UpdateConfig(dir string, edit func(cfg *config.Config) error) (*config.Config, bool, error) {
+	cfg, err := config.LoadProject(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	workflow := slices.Clone(cfg.GetWorkflow())
+	autoAdvance := cfg.AutoAdvance
+
+	if err := edit(cfg); err != nil {
+		return nil, false, err
+	}
+
+	var st *state.State
+	if !slices.Equal(workflow, cfg.GetWorkflow()) || autoAdvance != cfg.AutoAdvance {
+		if st, err = state.Load(dir); err != nil {
+			return nil, false, err
+		}
+		if !slices.Equal(workflow, cfg.GetWorkflow()) {
+			st.SetWorkflow(cfg.GetWorkflow())
+		}
+		if autoAdvance != cfg.AutoAdvance {
+			st.Confidence = cfg.AutoAdvance
+		}
+	}
+
+	if err := config.Save(dir, cfg); err != nil {
+		return nil, false, err
+	}
+	if st != nil {
+		if err := state.Save(dir, st); err != nil {
+			return nil, false, err
+		}
+	}
+	return cfg, st != nil, nil
+}
@@ -0,0 +1,66 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PhaseMeta is the optional YAML frontmatter of a phase plan.
+type PhaseMeta struct {
+	Designs []string `yaml:"designs,omitempty"` // design files this phase needs (e.g. "api.md")
+	Tags    []string `yaml:"tags,omitempty"`    // include designs carrying any of these tags
+	Scope   []string `yaml:"scope,omitempty"`   // files the phase may change (globs, "**" allowed)
+	Tasks   []string `yaml:"tasks,omitempty"`   // task titles (instead of a "## Tasks" section)
+}
+
+// DesignMeta is the optional YAML frontmatter of a design document.
+type DesignMeta struct {
+	Tags   []string `yaml:"tags,omitempty"`   // topics this design covers
+	Always bool     `yaml:"always,omitempty"` // include in every phase brief
+	Order  *int     `yaml:"order,omitempty"`  // position in briefs (lower first, before unordered designs)
+}
+
+// SplitFrontmatter separates a leading "---" delimited YAML block from the body.
+// If the content has no frontmatter, the returned frontmatter is empty.
+func SplitFrontmatter(content string) (string, string) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", content
+	}
+
+	// Searching from the opening delimiter's newline also finds the closing
+	// delimiter of an empty block ("---\n---")
+	rest := normalized[len("---"):]
+	end := strings.Index(rest, "\n---")
+	if end == -1 {
+		return "", content
+	}
+
+	// The closing delimiter must be a line of its own
+	after := rest[end+len("\n---"):]
+	if after != "" && !strings.HasPrefix(after, "\n") {
+		return "", content
+	}
+
+	return strings.TrimPrefix(rest[:end], "\n"), strings.TrimPrefix(after, "\n")
+}
+
+// ParseFrontmatter decodes the frontmatter of content into out and returns the body.
+func ParseFrontmatter(content string, out interface{}) (string, error) {
+	fm, body := SplitFrontmatter(content)
+	if strings.TrimSpace(fm) == "" {
+		return body, nil
+	}
+	if err := yaml.Unmarshal([]byte(fm), out); err != nil {
+		return body, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return body, nil
+}
+
+// StripFrontmatter returns content without its frontmatter block.
+func StripFrontmatter(content string) string {
+	_, body := SplitFrontmatter(content)
+	return body
+}
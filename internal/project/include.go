@@ -0,0 +1,139 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// includePattern matches include directives:
+//
+//	<!-- foreman:include db/migrations/001_init.sql -->
+//	<!-- foreman:include api/openapi.yaml#L10-L42 -->
+var includePattern = regexp.MustCompile(`<!--\s*foreman:include\s+(\S+)\s*-->`)
+
+// includeTarget is a parsed include directive. Start and End are 1-based
+// inclusive line numbers; zero means the whole file.
+type includeTarget struct {
+	Path  string
+	Start int
+	End   int
+}
+
+// parseIncludeTarget parses "path", "path#L10" or "path#L10-L20".
+func parseIncludeTarget(spec string) (includeTarget, error) {
+	path, lines, hasRange := strings.Cut(spec, "#")
+	target := includeTarget{Path: path}
+	if path == "" {
+		return target, fmt.Errorf("include %q: missing path", spec)
+	}
+	if !hasRange {
+		return target, nil
+	}
+
+	from, to, isSpan := strings.Cut(lines, "-")
+	start, err := strconv.Atoi(strings.TrimPrefix(from, "L"))
+	if err != nil || start < 1 {
+		return target, fmt.Errorf("include %q: invalid line range (expected #L10 or #L10-L20)", spec)
+	}
+	end := start
+	if isSpan {
+		end, err = strconv.Atoi(strings.TrimPrefix(to, "L"))
+		if err != nil || end < start {
+			return target, fmt.Errorf("include %q: invalid line range (expected #L10 or #L10-L20)", spec)
+		}
+	}
+	target.Start, target.End = start, end
+	return target, nil
+}
+
+// ExpandIncludes replaces include directives in content, which was read from
+// path, with the referenced files. Include paths are relative to the project
+// root and may not leave it. Included files are expanded recursively; paths
+// outside the root, cycles, missing files and bad line ranges are returned as
+// errors and marked inline.
+func ExpandIncludes(root, path, content string) (string, []error) {
+	return expandIncludes(root, content, []string{filepath.Clean(path)})
+}
+
+func expandIncludes(root, content string, stack []string) (string, []error) {
+	var errs []error
+	expanded := includePattern.ReplaceAllStringFunc(content, func(directive string) string {
+		spec := includePattern.FindStringSubmatch(directive)[1]
+		text, nestedErrs := readInclude(root, spec, stack)
+		errs = append(errs, nestedErrs...)
+		return text
+	})
+	return expanded, errs
+}
+
+// readInclude reads and expands one include target.
+func readInclude(root, spec string, stack []string) (string, []error) {
+	failed := func(err error) (string, []error) {
+		return fmt.Sprintf("_[include failed: %v]_", err), []error{err}
+	}
+
+	target, err := parseIncludeTarget(spec)
+	if err != nil {
+		return failed(err)
+	}
+
+	path := filepath.Clean(filepath.Join(root, filepath.FromSlash(target.Path)))
+	if rel, err := filepath.Rel(root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return failed(fmt.Errorf("include %s: outside the project root", target.Path))
+	}
+	for i, seen := range stack {
+		if seen == path {
+			var chain []string
+			cycle := append(append([]string{}, stack[i:]...), path)
+			for _, p := range cycle {
+				if rel, err := filepath.Rel(root, p); err == nil {
+					p = filepath.ToSlash(rel)
+				}
+				chain = append(chain, p)
+			}
+			return failed(fmt.Errorf("include cycle: %s", strings.Join(chain, " → ")))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return failed(fmt.Errorf("include %s: file not found", target.Path))
+	}
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	if target.Start > 0 {
+		lines := strings.Split(text, "\n")
+		if target.Start > len(lines) {
+			return failed(fmt.Errorf("include %s: line %d is past the end of the file (%d lines)", spec, target.Start, len(lines)))
+		}
+		end := target.End
+		if end > len(lines) {
+			end = len(lines)
+		}
+		text = strings.Join(lines[target.Start-1:end], "\n")
+	}
+
+	return expandIncludes(root, text, append(append([]string{}, stack...), path))
+}
+
+// CheckIncludes returns the problems with the include directives of a file.
+// A missing file has no includes to check.
+func CheckIncludes(root, path string) []error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	_, errs := ExpandIncludes(root, path, string(data))
+	return errs
+}
+
+// readExpanded reads a file and expands its includes, or returns the placeholder if it's missing.
+func readExpanded(root, path, placeholder string) string {
+	content := ReadFileContent(path, placeholder)
+	expanded, _ := ExpandIncludes(root, path, content)
+	return expanded
+}
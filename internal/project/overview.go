@@ -0,0 +1,344 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+)
+
+var (
+	overviewHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	overviewItemPattern     = regexp.MustCompile(`^(\s*)(\d+(?:\.\d+)*)[.)]\s+(.+)$`)
+	overviewBulletPattern   = regexp.MustCompile(`(?i)^(\s*)[-*+]\s+(?:\*\*|__)?phase\s+(\d+(?:\.\d+)*)\s*[:.)—–-]\s*(.+)$`)
+	overviewNumberPattern   = regexp.MustCompile(`(?i)^(?:phase\s+)?(\d+(?:\.\d+)*)\.?$`)
+	overviewTableSeparator  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	overviewEmphasisPattern = regexp.MustCompile("^(?:\\*\\*|__|\\*|_|`)(.+?)(?:\\*\\*|__|\\*|_|`)(.*)$")
+)
+
+// Table headers that name the title and goal columns of an overview table.
+var (
+	overviewTitleHeaders = []string{"title", "name", "phase"}
+	overviewGoalHeaders  = []string{"goal", "goals", "description", "summary", "objective", "outcome", "deliverables"}
+)
+
+// OverviewPhase is a phase listed in phases/overview.md.
+type OverviewPhase struct {
+	Name    string // phase name: given explicitly ("`2-backend`") or "<number>-<slug of title>"
+	Numbers []int  // number path, e.g. [2 1] for "2.1"
+	Title   string
+	Goal    string // one-line goal, if the entry has one
+}
+
+// Number returns the dotted number of the phase, e.g. "2.1".
+func (p OverviewPhase) Number() string {
+	return PhaseID{Numbers: p.Numbers}.Number()
+}
+
+// ParseOverview returns the phases listed in an overview: the numbered list
+// items ("1. **Setup** — goal", "- Phase 1: Setup") or table rows of its
+// phases section, or of the whole document if no heading mentions phases.
+// Indented list items are nested under the item above them.
+func ParseOverview(content string) []OverviewPhase {
+	lines := overviewSection(StripFrontmatter(content))
+
+	var phases []OverviewPhase
+	type level struct {
+		indent  int
+		numbers []int
+	}
+	var stack []level
+	var header []string
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "|") {
+			cells := splitTableRow(trimmed)
+			if i+1 < len(lines) && overviewTableSeparator.MatchString(strings.TrimSpace(lines[i+1])) {
+				header = cells
+				continue
+			}
+			if overviewTableSeparator.MatchString(trimmed) {
+				continue
+			}
+			if phase, ok := parseOverviewRow(header, cells); ok {
+				phases = append(phases, phase)
+			}
+			continue
+		}
+		header = nil
+
+		match := overviewItemPattern.FindStringSubmatch(line)
+		if match == nil {
+			match = overviewBulletPattern.FindStringSubmatch(line)
+		}
+		if match == nil {
+			continue
+		}
+
+		indent := len(strings.ReplaceAll(match[1], "\t", "    "))
+		numbers := parseNumbers(match[2])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		// A plain number under an indented parent continues its number path
+		if len(numbers) == 1 && len(stack) > 0 {
+			numbers = append(append([]int{}, stack[len(stack)-1].numbers...), numbers[0])
+		}
+		stack = append(stack, level{indent: indent, numbers: numbers})
+
+		name, title, goal := splitOverviewEntry(match[3])
+		phases = append(phases, newOverviewPhase(numbers, name, title, goal))
+	}
+
+	return phases
+}
+
+// overviewSection returns the lines of the overview's phases section: the
+// first heading below the title that mentions phases, up to the next heading
+// of the same or a higher level. Without such a heading it returns every line.
+func overviewSection(body string) []string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	start, depth := -1, 0
+	for i, line := range lines {
+		match := overviewHeadingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if start >= 0 {
+			if len(match[1]) <= depth {
+				return lines[start:i]
+			}
+			continue
+		}
+		if len(match[1]) > 1 && strings.Contains(strings.ToLower(match[2]), "phase") {
+			start, depth = i+1, len(match[1])
+		}
+	}
+	if start >= 0 {
+		return lines[start:]
+	}
+	return lines
+}
+
+// splitOverviewEntry splits a list item into an explicit phase name, a title and a goal.
+// Supported forms: "**Title** — goal", "`2-backend` Title: goal", "Title - goal".
+func splitOverviewEntry(text string) (string, string, string) {
+	text = strings.TrimSpace(text)
+
+	name := ""
+	if strings.HasPrefix(text, "`") {
+		if end := strings.Index(text[1:], "`"); end >= 0 {
+			if candidate := text[1 : end+1]; IsPhaseName(candidate) {
+				name = candidate
+				text = trimSeparators(text[end+2:])
+			}
+		}
+	}
+
+	if match := overviewEmphasisPattern.FindStringSubmatch(text); match != nil {
+		return name, strings.TrimSpace(match[1]), cleanCell(trimSeparators(match[2]))
+	}
+	for _, sep := range []string{" — ", " – ", " - ", ": "} {
+		if title, goal, ok := strings.Cut(text, sep); ok {
+			return name, cleanCell(title), cleanCell(goal)
+		}
+	}
+	return name, cleanCell(text), ""
+}
+
+// parseOverviewRow reads a table row. The phase number (or name) is the first
+// cell that looks like one; title and goal come from their named columns, or
+// from the cells that follow the number.
+func parseOverviewRow(header, cells []string) (OverviewPhase, bool) {
+	numberCol := -1
+	var numbers []int
+	name := ""
+	for i, cell := range cells {
+		value := cleanCell(cell)
+		if match := overviewNumberPattern.FindStringSubmatch(value); match != nil {
+			numberCol, numbers = i, parseNumbers(match[1])
+			break
+		}
+		if id, err := ParsePhaseID(value); err == nil {
+			numberCol, numbers, name = i, id.Numbers, value
+			break
+		}
+	}
+	if numberCol < 0 {
+		return OverviewPhase{}, false
+	}
+
+	titleCol := headerColumn(header, overviewTitleHeaders, numberCol)
+	goalCol := headerColumn(header, overviewGoalHeaders, numberCol)
+	if titleCol < 0 {
+		for i := numberCol + 1; i < len(cells); i++ {
+			if i != goalCol && cleanCell(cells[i]) != "" {
+				titleCol = i
+				break
+			}
+		}
+	}
+	if goalCol < 0 && titleCol >= 0 {
+		for i := titleCol + 1; i < len(cells); i++ {
+			if cleanCell(cells[i]) != "" {
+				goalCol = i
+				break
+			}
+		}
+	}
+
+	title, goal := "", ""
+	if titleCol >= 0 && titleCol < len(cells) {
+		title = cleanCell(cells[titleCol])
+	}
+	if goalCol >= 0 && goalCol < len(cells) {
+		goal = cleanCell(cells[goalCol])
+	}
+	if title == "" && name == "" {
+		return OverviewPhase{}, false
+	}
+	return newOverviewPhase(numbers, name, title, goal), true
+}
+
+// newOverviewPhase fills in the phase name and title from whichever of them is known.
+func newOverviewPhase(numbers []int, name, title, goal string) OverviewPhase {
+	phase := OverviewPhase{Name: name, Numbers: numbers, Title: title, Goal: goal}
+	if phase.Name == "" {
+		slug := Slug(title)
+		if slug == "" {
+			slug = "phase"
+		}
+		phase.Name = phase.Number() + "-" + slug
+	}
+	if phase.Title == "" {
+		phase.Title = titleFromPhaseName(phase.Name)
+	}
+	return phase
+}
+
+// headerColumn returns the index of the first header cell named one of names, skipping column skip.
+func headerColumn(header, names []string, skip int) int {
+	for i, cell := range header {
+		if i == skip {
+			continue
+		}
+		value := strings.ToLower(cleanCell(cell))
+		for _, name := range names {
+			if value == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTableRow splits "| a | b |" into its trimmed cells.
+func splitTableRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// cleanCell strips surrounding markdown emphasis and code marks from a value.
+func cleanCell(value string) string {
+	value = strings.TrimSpace(value)
+	for {
+		match := overviewEmphasisPattern.FindStringSubmatch(value)
+		if match == nil || strings.TrimSpace(match[2]) != "" {
+			return value
+		}
+		value = strings.TrimSpace(match[1])
+	}
+}
+
+// trimSeparators removes the dash or colon between an entry's title and goal.
+func trimSeparators(text string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), ":—–-"))
+}
+
+// parseNumbers parses a dotted number such as "2.1".
+func parseNumbers(number string) []int {
+	var numbers []int
+	for _, part := range strings.Split(number, ".") {
+		n, _ := strconv.Atoi(part)
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// OverviewDiff lists where phases/overview.md and the phase plan files disagree.
+// Entries are matched by phase number, so "2.1" in the overview matches both
+// 2.1-auth.md and 2-backend/1-auth.md.
+type OverviewDiff struct {
+	Listed   []OverviewPhase // every phase listed in the overview
+	Missing  []OverviewPhase // listed in the overview but without a plan file
+	Unlisted []string        // plan files whose number the overview doesn't list
+}
+
+// DiffOverview compares the phases listed in the overview with the phase plan files.
+func DiffOverview(root, dir string) (*OverviewDiff, error) {
+	names, err := ListPhaseNames(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &OverviewDiff{Listed: ParseOverview(ReadPhaseOverview(root, dir))}
+
+	files := make(map[string]bool)
+	for _, name := range names {
+		id, _ := ParsePhaseID(name)
+		files[id.Number()] = true
+	}
+
+	listed := make(map[string]bool)
+	for _, phase := range diff.Listed {
+		if listed[phase.Number()] {
+			continue
+		}
+		listed[phase.Number()] = true
+		if !files[phase.Number()] {
+			diff.Missing = append(diff.Missing, phase)
+		}
+	}
+
+	for _, name := range names {
+		id, _ := ParsePhaseID(name)
+		if !listed[id.Number()] {
+			diff.Unlisted = append(diff.Unlisted, name)
+		}
+	}
+
+	return diff, nil
+}
+
+// GeneratePhases creates a plan stub for every phase listed in the overview
+// that has no plan file yet, and returns the names of the created phases.
+func GeneratePhases(root, dir string, cfg *config.Config) ([]string, error) {
+	if !cfg.HasPhasesPhase() {
+		return nil, fmt.Errorf("the %s workflow has no phases stage", strings.Join(cfg.GetWorkflow(), " → "))
+	}
+
+	diff, err := DiffOverview(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Listed) == 0 {
+		return nil, fmt.Errorf("no phases found in %s: list them as a numbered list or table", PhaseOverviewPath(dir))
+	}
+
+	var created []string
+	for _, phase := range diff.Missing {
+		if err := writeNewFile(PhasePlanPath(dir, phase.Name), phasePlanContent(phase.Name, phase.Title, phase.Goal, cfg)); err != nil {
+			return created, err
+		}
+		created = append(created, phase.Name)
+	}
+	return created, nil
+}
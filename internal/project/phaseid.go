@@ -0,0 +1,93 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// phaseSegmentPattern matches one level of a phase name: "2-backend", "10-deploy", "2.1-auth".
+var phaseSegmentPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)-([^/]+)$`)
+
+// PhaseID is the parsed identifier of a phase. Nested phases live in
+// subdirectories ("2-backend/1-auth") or use dotted numbers ("2.1-auth");
+// both yield the number path [2 1].
+type PhaseID struct {
+	Name    string // full phase name, e.g. "2-backend/1-auth"
+	Numbers []int  // number path, e.g. [2 1]
+}
+
+// ParsePhaseID parses a phase name. Every slash-separated segment must start
+// with a number (or dotted numbers) followed by '-' and a label.
+func ParsePhaseID(name string) (PhaseID, error) {
+	id := PhaseID{Name: name}
+	if name == "" {
+		return id, fmt.Errorf("empty phase name")
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		match := phaseSegmentPattern.FindStringSubmatch(segment)
+		if match == nil {
+			return id, fmt.Errorf("invalid phase name %q: expected <number>-<name> (e.g. 1-setup, 10-deploy, 2.1-auth)", name)
+		}
+		for _, part := range strings.Split(match[1], ".") {
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return id, fmt.Errorf("invalid phase number in %q: %w", name, err)
+			}
+			id.Numbers = append(id.Numbers, n)
+		}
+	}
+
+	return id, nil
+}
+
+// IsPhaseName reports whether name is a valid phase name.
+func IsPhaseName(name string) bool {
+	_, err := ParsePhaseID(name)
+	return err == nil
+}
+
+// Number returns the dotted number of the phase, e.g. "2.1".
+func (id PhaseID) Number() string {
+	parts := make([]string, len(id.Numbers))
+	for i, n := range id.Numbers {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Depth returns how deeply the phase is nested (0 for top-level phases).
+func (id PhaseID) Depth() int {
+	return len(id.Numbers) - 1
+}
+
+// Less orders phases naturally: by number path (2 < 2.1 < 3 < 10), then by name.
+func (id PhaseID) Less(other PhaseID) bool {
+	for i := 0; i < len(id.Numbers) && i < len(other.Numbers); i++ {
+		if id.Numbers[i] != other.Numbers[i] {
+			return id.Numbers[i] < other.Numbers[i]
+		}
+	}
+	if len(id.Numbers) != len(other.Numbers) {
+		return len(id.Numbers) < len(other.Numbers)
+	}
+	return id.Name < other.Name
+}
+
+// SortPhaseNames sorts phase names in natural order. Invalid names sort last, by name.
+func SortPhaseNames(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		a, errA := ParsePhaseID(names[i])
+		b, errB := ParsePhaseID(names[j])
+		if errA != nil || errB != nil {
+			if (errA == nil) != (errB == nil) {
+				return errA == nil
+			}
+			return names[i] < names[j]
+		}
+		return a.Less(b)
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/thinkshake/foreman/internal/blueprint"
 	"github.com/thinkshake/foreman/internal/config"
 	"github.com/thinkshake/foreman/internal/state"
+	"gopkg.in/yaml.v3"
 )
 
 const ForemanDir = ".foreman"
@@ -32,51 +36,68 @@ func FindRoot(dir string) (string, error) {
 	return "", fmt.Errorf("no .foreman/ directory found (walked up from %s)\nRun 'foreman init' to create a project", dir)
 }
 
-// ForemanPath returns the path to .foreman/ for a project root.
+// ForemanPath returns the path to .foreman/ for a project root, which is
+// also the directory of the main track (see config.TrackDir).
 func ForemanPath(root string) string {
 	return filepath.Join(root, ForemanDir)
 }
 
 // RequirementsPath returns the path to requirements.md.
-func RequirementsPath(root string) string {
-	return filepath.Join(ForemanPath(root), "requirements.md")
+func RequirementsPath(dir string) string {
+	return filepath.Join(dir, "requirements.md")
 }
 
 // DesignsPath returns the path to the designs directory.
-func DesignsPath(root string) string {
-	return filepath.Join(ForemanPath(root), "designs")
+func DesignsPath(dir string) string {
+	return filepath.Join(dir, "designs")
 }
 
 // PhasesPath returns the path to the phases directory.
-func PhasesPath(root string) string {
-	return filepath.Join(ForemanPath(root), "phases")
+func PhasesPath(dir string) string {
+	return filepath.Join(dir, "phases")
 }
 
 // BriefsPath returns the path to the briefs directory.
-func BriefsPath(root string) string {
-	return filepath.Join(ForemanPath(root), "briefs")
+func BriefsPath(dir string) string {
+	return filepath.Join(dir, "briefs")
+}
+
+// ReportsPath returns the path to the phase reports directory.
+func ReportsPath(dir string) string {
+	return filepath.Join(dir, "reports")
 }
 
 // PhaseOverviewPath returns the path to phases/overview.md.
-func PhaseOverviewPath(root string) string {
-	return filepath.Join(PhasesPath(root), "overview.md")
+func PhaseOverviewPath(dir string) string {
+	return filepath.Join(PhasesPath(dir), "overview.md")
 }
 
 // PhasePlanPath returns the path to a specific phase plan.
-func PhasePlanPath(root, phaseName string) string {
-	return filepath.Join(PhasesPath(root), phaseName+".md")
+func PhasePlanPath(dir, phaseName string) string {
+	return filepath.Join(PhasesPath(dir), filepath.FromSlash(phaseName)+".md")
 }
 
 // BriefPath returns the path to a specific brief.
-func BriefPath(root, phaseName string) string {
-	return filepath.Join(BriefsPath(root), phaseName+".md")
+func BriefPath(dir, phaseName string) string {
+	return filepath.Join(BriefsPath(dir), filepath.FromSlash(phaseName)+".md")
+}
+
+// ReportPath returns the path to a phase's completion report.
+func ReportPath(dir, phaseName string) string {
+	return filepath.Join(ReportsPath(dir), filepath.FromSlash(phaseName)+".md")
 }
 
 // InitOptions configures project initialization.
 type InitOptions struct {
-	Name   string
-	Preset string // minimal, light, full (or aliases: nightly, product)
-	TDD    bool   // Enable TDD mode
+	Name        string
+	Description string
+	TechStack   []string
+	Preset      string            // minimal, light, full (or aliases: nightly, product)
+	TDD         bool              // Enable TDD mode
+	Testing     *config.Testing   // Testing configuration; overrides TDD when set
+	Reviewers   map[string]string // stage -> "auto" or "human", on top of the preset's reviewers
+	Template    string            // blueprint: a built-in name or a directory (see package blueprint)
+	Track       string            // named track to add to an existing project; "" creates the project
 }
 
 // Init creates a new .foreman/ directory with v2 structure (legacy).
@@ -85,55 +106,138 @@ func Init(dir, name string) (string, error) {
 }
 
 // InitWithOptions creates a new .foreman/ directory with v3 features.
+// With opts.Track, the named track is created in the existing project
+// instead, under .foreman/tracks/<name>/.
 func InitWithOptions(dir string, opts InitOptions) (string, error) {
-	foremanDir := filepath.Join(dir, ForemanDir)
-	if _, err := os.Stat(foremanDir); err == nil {
+	track := opts.Track
+	if track == "" {
+		track = config.MainTrack
+	}
+	foremanDir := config.TrackDir(dir, track)
+	var main *config.Config
+	if track != config.MainTrack {
+		if err := config.ValidateTrackName(track); err != nil {
+			return "", err
+		}
+		var err error
+		if main, err = config.LoadProject(ForemanPath(dir)); err != nil {
+			return "", fmt.Errorf("cannot create track %s: no foreman project in %s (run 'foreman init' first): %w", track, dir, err)
+		}
+		if _, err := os.Stat(foremanDir); err == nil {
+			return "", fmt.Errorf("track %s already exists in %s", track, dir)
+		}
+	} else if _, err := os.Stat(foremanDir); err == nil {
 		return "", fmt.Errorf(".foreman/ already exists in %s", dir)
 	}
 
-	// Determine project name
+	// Determine project name; tracks share the project's
 	name := opts.Name
+	if name == "" && main != nil {
+		name = main.Name
+	}
 	if name == "" {
 		name = filepath.Base(dir)
 	}
 
+	// A blueprint supplies the preset unless one is given
+	var bp *blueprint.Blueprint
+	var bpFiles []blueprint.File
+	preset := opts.Preset
+	if opts.Template != "" {
+		var err error
+		if bp, err = blueprint.Load(opts.Template); err != nil {
+			return "", err
+		}
+		if bpFiles, err = bp.Files(name); err != nil {
+			return "", err
+		}
+		if preset == "" {
+			preset = bp.Preset()
+		}
+	}
+
+	// Resolve the preset; unknown names are an error rather than an empty
+	// preset. A track may also use the presets of its project.
+	var presetDef *config.Preset
+	if preset != "" {
+		presetRoot := ""
+		if main != nil {
+			presetRoot = dir
+		}
+		var err error
+		if presetDef, err = config.FindPreset(presetRoot, preset); err != nil {
+			return "", err
+		}
+	}
+
 	// Create config.yaml with preset and optional TDD
 	var cfg *config.Config
-	if opts.Preset != "" {
-		if opts.TDD {
-			cfg = config.NewWithTesting(name, opts.Preset, true)
-		} else {
-			cfg = config.NewWithPreset(name, opts.Preset)
-		}
+	if presetDef != nil {
+		cfg = config.NewFromPreset(name, presetDef)
 	} else {
 		cfg = config.NewDefault(name)
-		if opts.TDD {
-			cfg.Testing = &config.Testing{
-				Style:    config.TestingStyleTDD,
-				Required: false,
-			}
+	}
+
+	if bp != nil {
+		if err := bp.ApplyConfig(cfg); err != nil {
+			return "", err
+		}
+		cfg.Name = name
+
+		// An explicit preset wins over the blueprint's workflow
+		if opts.Preset != "" {
+			base := config.NewFromPreset(name, presetDef)
+			cfg.Preset, cfg.Workflow, cfg.AutoAdvance = base.Preset, base.Workflow, base.AutoAdvance
+		}
+	}
+
+	if opts.TDD && !cfg.IsTDDEnabled() {
+		cfg.Testing = &config.Testing{
+			Style:    config.TestingStyleTDD,
+			Required: false,
+		}
+	}
+
+	cfg.Description = opts.Description
+	if len(opts.TechStack) > 0 {
+		cfg.TechStack = opts.TechStack
+	}
+	if main != nil {
+		inheritTrackSettings(cfg, main)
+	}
+	if opts.Testing != nil {
+		cfg.Testing = opts.Testing
+	}
+	for stage, reviewer := range opts.Reviewers {
+		if reviewer != "auto" && reviewer != "human" {
+			return "", fmt.Errorf("invalid reviewer for %s: %s (must be 'auto' or 'human')", stage, reviewer)
 		}
+		if !slices.Contains(cfg.GetWorkflow(), stage) {
+			return "", fmt.Errorf("cannot set reviewer for %s: not a stage of the %s workflow", stage, strings.Join(cfg.GetWorkflow(), " → "))
+		}
+		cfg.Reviewers.SetReviewer(stage, reviewer)
 	}
 
 	// Determine workflow from config
 	workflow := cfg.GetWorkflow()
 	quickMode := cfg.IsQuickPreset()
 	minimalMode := cfg.IsMinimalPreset()
+	skipGates := presetDef != nil && presetDef.Gates.Skip
 	hasDesign := cfg.HasDesignPhase()
 	hasPhases := cfg.HasPhasesPhase()
 
 	// Create directory structure
 	dirs := []string{
 		foremanDir,
-		BriefsPath(dir),
+		BriefsPath(foremanDir),
 	}
 
 	// Full mode includes designs and phases directories
 	if hasDesign {
-		dirs = append(dirs, DesignsPath(dir))
+		dirs = append(dirs, DesignsPath(foremanDir))
 	}
 	if hasPhases {
-		dirs = append(dirs, PhasesPath(dir))
+		dirs = append(dirs, PhasesPath(foremanDir))
 	}
 
 	for _, d := range dirs {
@@ -142,7 +246,7 @@ func InitWithOptions(dir string, opts InitOptions) (string, error) {
 		}
 	}
 
-	if err := config.Save(dir, cfg); err != nil {
+	if err := config.Save(foremanDir, cfg); err != nil {
 		return "", fmt.Errorf("failed to create config.yaml: %w", err)
 	}
 
@@ -153,9 +257,9 @@ func InitWithOptions(dir string, opts InitOptions) (string, error) {
 	} else if quickMode {
 		st = state.NewQuickMode("", cfg.AutoAdvance)
 	} else {
-		st = state.NewWithWorkflow(workflow, cfg.AutoAdvance, false)
+		st = state.NewWithWorkflow(workflow, cfg.AutoAdvance, skipGates)
 	}
-	if err := state.Save(dir, st); err != nil {
+	if err := state.Save(foremanDir, st); err != nil {
 		return "", fmt.Errorf("failed to create state.yaml: %w", err)
 	}
 
@@ -191,62 +295,141 @@ _Define what this project should accomplish. Include:_
 Replace this placeholder with actual requirements.
 `
 	}
-	if err := os.WriteFile(RequirementsPath(dir), []byte(reqContent), 0644); err != nil {
+	if err := os.WriteFile(RequirementsPath(foremanDir), []byte(reqContent), 0644); err != nil {
 		return "", fmt.Errorf("failed to create requirements.md: %w", err)
 	}
 
+	// Blueprint documents replace the placeholder; designs and phases only
+	// when the workflow has those stages
+	for _, file := range bpFiles {
+		if (strings.HasPrefix(file.Path, "designs/") && !hasDesign) || (strings.HasPrefix(file.Path, "phases/") && !hasPhases) {
+			continue
+		}
+		path := filepath.Join(foremanDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", file.Path, err)
+		}
+	}
+
 	return dir, nil
 }
 
-// SyncPhasesToState reads phase files and updates state with phase list.
-func SyncPhasesToState(root string, st *state.State) error {
-	phasesDir := PhasesPath(root)
-	
+// inheritTrackSettings copies the settings of the whole repository from the
+// main config into a new track's config: the tech stack (unless given) and
+// the git integration.
+func inheritTrackSettings(cfg, main *config.Config) {
+	if len(cfg.TechStack) == 0 {
+		cfg.TechStack = main.TechStack
+	}
+	if main.Git == nil || cfg.Git != nil {
+		return
+	}
+	git := *main.Git
+	cfg.Git = &git
+}
+
+// ListPhaseNames returns the names of all phase plans in natural order
+// (1-setup, 2-backend, 2-backend/1-auth, 2.2-api, 10-deploy). Plans in
+// subdirectories are named by their relative path; overview.md files and
+// files without a valid phase name are skipped.
+func ListPhaseNames(dir string) ([]string, error) {
+	names, _, err := scanPhaseFiles(dir)
+	return names, err
+}
+
+// InvalidPhaseFiles returns plan files under phases/ whose names are not valid
+// phase names, relative to phases/.
+func InvalidPhaseFiles(dir string) ([]string, error) {
+	_, invalid, err := scanPhaseFiles(dir)
+	return invalid, err
+}
+
+// scanPhaseFiles walks phases/ and splits its .md files into valid phase
+// names (sorted naturally) and invalid file paths.
+func scanPhaseFiles(dir string) ([]string, []string, error) {
+	phasesDir := PhasesPath(dir)
+
 	// Check if phases directory exists
 	if _, err := os.Stat(phasesDir); os.IsNotExist(err) {
-		return nil // No phases yet
+		return nil, nil, nil // No phases yet
 	}
-	
-	entries, err := os.ReadDir(phasesDir)
+
+	var names, invalid []string
+	err := filepath.WalkDir(phasesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != phasesDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "overview.md" || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(phasesDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Remove .md extension to get phase name
+		name := strings.TrimSuffix(rel, ".md")
+		if IsPhaseName(name) {
+			names = append(names, name)
+		} else {
+			invalid = append(invalid, rel)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read phases directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read phases directory: %w", err)
+	}
+
+	SortPhaseNames(names)
+	return names, invalid, nil
+}
+
+// SyncPhasesToState reads phase files and updates state with phase list.
+func SyncPhasesToState(dir string, st *state.State) error {
+	// Check if phases directory exists
+	if _, err := os.Stat(PhasesPath(dir)); os.IsNotExist(err) {
+		return nil // No phases yet
 	}
 	
 	// Collect phase names from files (excluding overview.md)
-	var phaseNames []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		
-		name := entry.Name()
-		if name == "overview.md" || !strings.HasSuffix(name, ".md") {
-			continue
-		}
-		
-		// Remove .md extension to get phase name
-		phaseName := strings.TrimSuffix(name, ".md")
-		phaseNames = append(phaseNames, phaseName)
+	phaseNames, err := ListPhaseNames(dir)
+	if err != nil {
+		return err
 	}
 	
-	// Update state with found phases, preserving existing status
-	existingPhases := make(map[string]string)
+	// Update state with found phases, preserving existing status, history and task status
+	existingPhases := make(map[string]state.Phase)
 	for _, phase := range st.Phases {
-		existingPhases[phase.Name] = phase.Status
+		existingPhases[phase.Name] = phase
 	}
 	
 	// Rebuild phases list
 	st.Phases = []state.Phase{}
 	for _, name := range phaseNames {
-		status := "planned" // default
-		if existingStatus, exists := existingPhases[name]; exists {
-			status = existingStatus
+		phase := state.Phase{Name: name, Status: "planned"} // default
+		if existing, exists := existingPhases[name]; exists {
+			phase = existing
 		}
 		
-		st.Phases = append(st.Phases, state.Phase{
-			Name:   name,
-			Status: status,
-		})
+		// Tasks follow the plan; plans with broken frontmatter keep their tasks.
+		// Tasks added to a done phase leave its status and history alone:
+		// 'foreman status' and 'foreman doctor' report the mismatch.
+		if tasks, err := ReadPhaseTasks(dir, name); err == nil {
+			phase.Tasks = mergeTasks(phase.Tasks, tasks)
+		}
+		
+		st.Phases = append(st.Phases, phase)
 	}
 	
 	return nil
@@ -261,46 +444,255 @@ func ReadFileContent(path, placeholder string) string {
 	return strings.TrimSpace(string(data))
 }
 
-// ReadRequirements reads the requirements.md file.
-func ReadRequirements(root string) string {
-	return ReadFileContent(RequirementsPath(root), "_No requirements defined yet._")
+// ReadRequirements reads the requirements.md file with its includes expanded.
+func ReadRequirements(root, dir string) string {
+	return readExpanded(root, RequirementsPath(dir), "_No requirements defined yet._")
 }
 
-// ReadDesigns reads all design documents and concatenates them.
-func ReadDesigns(root string) string {
-	designsDir := DesignsPath(root)
+// Design is a single design document with its frontmatter parsed.
+type Design struct {
+	Name    string     // path within designs/, e.g. "api.md" or "backend/api.md"
+	Content string     // document body without frontmatter
+	Meta    DesignMeta // tags, always flag and order
+}
+
+// DesignIndexFile optionally fixes the order of design documents.
+const DesignIndexFile = "index.yaml"
+
+// DesignIndex is the schema of designs/index.yaml.
+type DesignIndex struct {
+	Order []string `yaml:"order"` // design paths within designs/, first to last
+}
+
+// ReadDesignIndex reads designs/index.yaml, returning nil if it doesn't exist.
+func ReadDesignIndex(dir string) (*DesignIndex, error) {
+	data, err := os.ReadFile(filepath.Join(DesignsPath(dir), DesignIndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 	
-	entries, err := os.ReadDir(designsDir)
+	var index DesignIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse designs/%s: %w", DesignIndexFile, err)
+	}
+	for i, name := range index.Order {
+		index.Order[i] = NormalizeDesignName(name)
+	}
+	return &index, nil
+}
+
+// ListDesignFiles returns the .md files under designs/, recursively, as
+// slash-separated paths relative to designs/ in lexical order.
+func ListDesignFiles(dir string) ([]string, error) {
+	designsDir := DesignsPath(dir)
+	if _, err := os.Stat(designsDir); os.IsNotExist(err) {
+		return nil, nil // No designs yet
+	} else if err != nil {
+		return nil, err
+	}
+	
+	var names []string
+	err := filepath.WalkDir(designsDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != designsDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(designsDir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	return names, err
+}
+
+// LoadDesigns reads all design documents that have content, ordered by
+// designs/index.yaml, then by their order frontmatter, then by path.
+func LoadDesigns(root, dir string) ([]Design, error) {
+	names, err := ListDesignFiles(dir)
 	if err != nil {
-		return "_No design documents found._"
+		return nil, err
+	}
+	index, err := ReadDesignIndex(dir)
+	if err != nil {
+		return nil, err
 	}
 	
-	var designs []string
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
+	var designs []Design
+	for _, name := range names {
+		path := filepath.Join(DesignsPath(dir), filepath.FromSlash(name))
+		var meta DesignMeta
+		body, err := ParseFrontmatter(readExpanded(root, path, ""), &meta)
+		if err != nil {
+			return nil, fmt.Errorf("designs/%s: %w", name, err)
 		}
-		
-		path := filepath.Join(designsDir, entry.Name())
-		content := ReadFileContent(path, "")
+		content := strings.TrimSpace(body)
 		if content != "" {
-			designs = append(designs, fmt.Sprintf("## %s\n\n%s", entry.Name(), content))
+			designs = append(designs, Design{Name: name, Content: content, Meta: meta})
+		}
+	}
+	
+	SortDesigns(designs, index)
+	return designs, nil
+}
+
+// SortDesigns orders designs: those listed in the index first (in its order),
+// then those with an order key (ascending), then the rest by path.
+func SortDesigns(designs []Design, index *DesignIndex) {
+	position := make(map[string]int)
+	if index != nil {
+		for i, name := range index.Order {
+			if _, seen := position[name]; !seen {
+				position[name] = i
+			}
+		}
+	}
+	
+	sort.SliceStable(designs, func(i, j int) bool {
+		a, b := designs[i], designs[j]
+		pa, inA := position[a.Name]
+		pb, inB := position[b.Name]
+		if inA || inB {
+			if inA && inB {
+				return pa < pb
+			}
+			return inA
+		}
+		if a.Meta.Order != nil || b.Meta.Order != nil {
+			if a.Meta.Order != nil && b.Meta.Order != nil && *a.Meta.Order != *b.Meta.Order {
+				return *a.Meta.Order < *b.Meta.Order
+			}
+			if (a.Meta.Order == nil) != (b.Meta.Order == nil) {
+				return a.Meta.Order != nil
+			}
 		}
+		return a.Name < b.Name
+	})
+}
+
+// FormatDesigns renders design documents as brief sections.
+func FormatDesigns(designs []Design) string {
+	var sections []string
+	for _, d := range designs {
+		sections = append(sections, fmt.Sprintf("## %s\n\n%s", d.Name, d.Content))
+	}
+	return strings.Join(sections, "\n\n---\n\n")
+}
+
+// ReadDesigns reads all design documents and concatenates them.
+func ReadDesigns(root, dir string) string {
+	designs, err := LoadDesigns(root, dir)
+	if err != nil {
+		return "_No design documents found._"
 	}
 	
 	if len(designs) == 0 {
 		return "_No design documents with content found._"
 	}
 	
-	return strings.Join(designs, "\n\n---\n\n")
+	return FormatDesigns(designs)
+}
+
+// NormalizeDesignName returns a design reference as a file name ("api" → "api.md").
+// Directory references ("backend/") are returned unchanged.
+func NormalizeDesignName(name string) string {
+	if strings.HasSuffix(name, ".md") || strings.HasSuffix(name, "/") {
+		return name
+	}
+	return name + ".md"
+}
+
+// SelectsDesigns reports whether the phase restricts which designs it receives.
+func (m PhaseMeta) SelectsDesigns() bool {
+	return len(m.Designs) > 0 || len(m.Tags) > 0
+}
+
+// SelectDesigns splits designs into those relevant to a phase and those omitted.
+// Designs marked always are included regardless; without a selection, all are included.
+func SelectDesigns(designs []Design, meta PhaseMeta) ([]Design, []Design) {
+	if !meta.SelectsDesigns() {
+		return designs, nil
+	}
+	
+	wanted := make(map[string]bool)
+	for _, name := range meta.Designs {
+		wanted[NormalizeDesignName(name)] = true
+	}
+	tags := make(map[string]bool)
+	for _, tag := range meta.Tags {
+		tags[tag] = true
+	}
+	
+	var included, omitted []Design
+	for _, d := range designs {
+		match := d.Meta.Always || wanted[d.Name]
+		for name := range wanted {
+			if strings.HasSuffix(name, "/") && strings.HasPrefix(d.Name, name) {
+				match = true
+			}
+		}
+		for _, tag := range d.Meta.Tags {
+			if tags[tag] {
+				match = true
+			}
+		}
+		if match {
+			included = append(included, d)
+		} else {
+			omitted = append(omitted, d)
+		}
+	}
+	
+	return included, omitted
 }
 
 // ReadPhaseOverview reads the phases/overview.md file.
-func ReadPhaseOverview(root string) string {
-	return ReadFileContent(PhaseOverviewPath(root), "_No phase overview defined yet._")
+func ReadPhaseOverview(root, dir string) string {
+	return readExpanded(root, PhaseOverviewPath(dir), "_No phase overview defined yet._")
+}
+
+// ReadPhasePlan reads a specific phase plan file without its frontmatter, with includes expanded.
+func ReadPhasePlan(root, dir, phaseName string) string {
+	content := readExpanded(root, PhasePlanPath(dir, phaseName), fmt.Sprintf("_No plan defined for phase %s._", phaseName))
+	return strings.TrimSpace(StripFrontmatter(content))
+}
+
+// ReadPhaseMeta reads the frontmatter of a phase plan.
+// A missing plan file yields empty metadata.
+func ReadPhaseMeta(dir, phaseName string) (PhaseMeta, error) {
+	var meta PhaseMeta
+	content := ReadFileContent(PhasePlanPath(dir, phaseName), "")
+	if _, err := ParseFrontmatter(content, &meta); err != nil {
+		return meta, fmt.Errorf("phase %s: %w", phaseName, err)
+	}
+	return meta, nil
+}
+
+// ReadPhaseReport reads a phase's completion report, or "" if there is none.
+func ReadPhaseReport(dir, phaseName string) string {
+	return ReadFileContent(ReportPath(dir, phaseName), "")
 }
 
-// ReadPhasePlan reads a specific phase plan file.
-func ReadPhasePlan(root, phaseName string) string {
-	return ReadFileContent(PhasePlanPath(root, phaseName), fmt.Sprintf("_No plan defined for phase %s._", phaseName))
-}
\ No newline at end of file
+// WritePhaseReport stores a phase's completion report in reports/.
+func WritePhaseReport(dir, phaseName, content string) error {
+	if err := os.MkdirAll(filepath.Dir(ReportPath(dir, phaseName)), 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	content = strings.TrimSpace(content) + "\n"
+	if err := os.WriteFile(ReportPath(dir, phaseName), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
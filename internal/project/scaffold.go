@@ -0,0 +1,314 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thinkshake/foreman/internal/config"
+)
+
+var (
+	// phaseHeadingPattern matches a plan's title line, e.g. "# Phase 2: Backend".
+	phaseHeadingPattern = regexp.MustCompile(`(?i)^#\s+(?:phase\s+[\d.]+\s*[:.—-]\s*)?(.+?)\s*$`)
+	// placeholderPattern matches a paragraph written entirely in _italics_,
+	// which the scaffolds and blueprints use for text to be replaced.
+	placeholderPattern = regexp.MustCompile(`^_[^_\s](?:.*[^_\s])?_$`)
+	goalHeadingPattern = regexp.MustCompile(`(?i)^##\s+goals?\s*$`)
+)
+
+// Placeholders returns the template placeholders left in a document:
+// paragraphs written entirely in _italics_, outside code blocks.
+func Placeholders(content string) []string {
+	var placeholders, paragraph []string
+	flush := func() {
+		if text := strings.Join(paragraph, " "); placeholderPattern.MatchString(text) {
+			placeholders = append(placeholders, text)
+		}
+		paragraph = nil
+	}
+
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			flush()
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	flush()
+	return placeholders
+}
+
+// PlanGoal returns the text of a phase plan's "## Goal" section and whether
+// the plan has one.
+func PlanGoal(content string) (string, bool) {
+	var goal []string
+	found, inSection := false, false
+	for _, line := range strings.Split(strings.ReplaceAll(StripFrontmatter(content), "\r\n", "\n"), "\n") {
+		if goalHeadingPattern.MatchString(strings.TrimSpace(line)) {
+			found, inSection = true, true
+			continue
+		}
+		if inSection && headingPattern.MatchString(line) {
+			break
+		}
+		if inSection {
+			goal = append(goal, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(goal, "\n")), found
+}
+
+// writeNewFile writes content to path, refusing to overwrite an existing file.
+func writeNewFile(path, content string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// titleFromSlug turns "data-model" into "Data Model".
+func titleFromSlug(slug string) string {
+	words := strings.FieldsFunc(slug, func(r rune) bool { return r == '-' || r == '_' })
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// testingNotes returns template lines describing the project's testing style.
+func testingNotes(cfg *config.Config) string {
+	if cfg.Testing == nil || cfg.Testing.Style == "" || cfg.Testing.Style == config.TestingStyleNone {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n## Tests\n\n")
+	switch cfg.Testing.Style {
+	case config.TestingStyleTDD:
+		b.WriteString("_TDD is enabled: list the tests to write before the implementation._\n")
+	case config.TestingStyleCoverage:
+		if cfg.Testing.MinCover > 0 {
+			b.WriteString(fmt.Sprintf("_Coverage target: %d%%. List the behavior the tests must cover._\n", cfg.Testing.MinCover))
+		} else {
+			b.WriteString("_List the behavior the tests must cover._\n")
+		}
+	}
+	if cfg.Testing.Framework != "" {
+		b.WriteString(fmt.Sprintf("\nFramework: %s\n", cfg.Testing.Framework))
+	}
+	return b.String()
+}
+
+// NewDesign creates designs/<name>.md from the design template and returns its path.
+// Names may include subdirectories ("backend/api").
+func NewDesign(dir, name string, cfg *config.Config) (string, error) {
+	if !cfg.HasDesignPhase() {
+		return "", fmt.Errorf("the %s workflow has no design stage", strings.Join(cfg.GetWorkflow(), " → "))
+	}
+
+	name = NormalizeDesignName(strings.TrimSpace(name))
+	clean := filepath.ToSlash(filepath.Clean(name))
+	if name == ".md" || strings.HasSuffix(name, "/") || filepath.IsAbs(name) || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid design name %q", name)
+	}
+
+	title := titleFromSlug(strings.TrimSuffix(filepath.Base(clean), ".md"))
+	content := fmt.Sprintf(`# %s
+
+## Overview
+
+_What this part of the system does and why it exists._
+
+## Components
+
+_The main pieces and their responsibilities._
+
+## Data Model
+
+_Entities, fields and relations (or "n/a")._
+
+## Interfaces
+
+_APIs, commands, events or file formats other parts depend on._
+
+## Decisions
+
+_Alternatives considered and why this design was chosen._
+`, title)
+
+	path := filepath.Join(DesignsPath(dir), filepath.FromSlash(clean))
+	if err := writeNewFile(path, content); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// NextPhaseName returns the name for a new phase titled title: the next free
+// number after the existing phases (under parent, if given) and a slug of the title.
+func NextPhaseName(dir, parent, title string) (string, error) {
+	slug := Slug(title)
+	if slug == "" {
+		return "", fmt.Errorf("phase title %q has no usable characters", title)
+	}
+
+	prefix := ""
+	if parent != "" {
+		if !IsPhaseName(parent) {
+			return "", fmt.Errorf("invalid parent phase name %q", parent)
+		}
+		prefix = parent + "/"
+	}
+
+	names, err := ListPhaseNames(dir)
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || strings.Contains(name[len(prefix):], "/") {
+			continue
+		}
+		id, err := ParsePhaseID(name[len(prefix):])
+		if err != nil {
+			continue
+		}
+		// The leading number of this level, e.g. 2 for "2.1-auth"
+		if n := id.Numbers[0]; n >= next {
+			next = n + 1
+		}
+	}
+
+	return prefix + strconv.Itoa(next) + "-" + slug, nil
+}
+
+// NewPhase creates the plan for a new phase from the phase template and
+// returns the phase name. parent nests the phase under an existing one.
+func NewPhase(dir, parent, title string, cfg *config.Config) (string, error) {
+	if !cfg.HasPhasesPhase() {
+		return "", fmt.Errorf("the %s workflow has no phases stage", strings.Join(cfg.GetWorkflow(), " → "))
+	}
+
+	name, err := NextPhaseName(dir, parent, title)
+	if err != nil {
+		return "", err
+	}
+
+	content := phasePlanContent(name, strings.TrimSpace(title), "", cfg)
+	if err := writeNewFile(PhasePlanPath(dir, name), content); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// phasePlanContent renders the phase plan template. An empty goal leaves a placeholder.
+func phasePlanContent(name, title, goal string, cfg *config.Config) string {
+	id, _ := ParsePhaseID(name)
+	if goal == "" {
+		goal = "_One or two sentences: what does this phase deliver?_"
+	}
+
+	return fmt.Sprintf(`# Phase %s: %s
+
+## Goal
+
+%s
+
+## Tasks
+
+<!-- One "- item" per task; track them with 'foreman task %s/<task> <status>'. -->
+
+## Deliverables
+
+_Files, commands or endpoints that exist when the phase is done._
+
+## Acceptance Criteria
+
+_How a reviewer verifies the phase is complete._
+%s`, id.Number(), title, goal, name, testingNotes(cfg))
+}
+
+// PhaseTitle returns the title of a phase: its plan's first heading without
+// the "Phase N:" prefix, or a title derived from its name.
+func PhaseTitle(dir, name string) string {
+	body := StripFrontmatter(ReadFileContent(PhasePlanPath(dir, name), ""))
+	for _, line := range strings.Split(body, "\n") {
+		if match := phaseHeadingPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			return match[1]
+		}
+	}
+
+	return titleFromPhaseName(name)
+}
+
+// titleFromPhaseName turns "2-backend/1-user-auth" into "User Auth".
+func titleFromPhaseName(name string) string {
+	segments := strings.Split(name, "/")
+	last := segments[len(segments)-1]
+	if _, label, ok := strings.Cut(last, "-"); ok {
+		return titleFromSlug(label)
+	}
+	return last
+}
+
+// NewOverview creates phases/overview.md listing the existing phases and returns its path.
+func NewOverview(dir string, cfg *config.Config) (string, error) {
+	if !cfg.HasPhasesPhase() {
+		return "", fmt.Errorf("the %s workflow has no phases stage", strings.Join(cfg.GetWorkflow(), " → "))
+	}
+
+	names, err := ListPhaseNames(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	for _, name := range names {
+		id, _ := ParsePhaseID(name)
+		indent := strings.Repeat("   ", id.Depth())
+		list.WriteString(fmt.Sprintf("%s%s. **%s**\n", indent, id.Number(), PhaseTitle(dir, name)))
+	}
+	if list.Len() == 0 {
+		list.WriteString("1. **Setup** — one-line goal\n")
+	}
+
+	content := fmt.Sprintf(`# Implementation Phases
+
+## Strategy
+
+_How the work is split up, and why in this order._
+
+## Phases
+
+%s
+## Dependencies
+
+_Which phases must finish before others can start._
+`, list.String())
+
+	path := PhaseOverviewPath(dir)
+	if err := writeNewFile(path, content); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,119 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// maxSlugLength keeps task IDs and generated file names short enough to type.
+const maxSlugLength = 40
+
+var (
+	tasksHeadingPattern = regexp.MustCompile(`(?i)^##\s+tasks\s*$`)
+	headingPattern      = regexp.MustCompile(`^#{1,2}\s`)
+	taskItemPattern     = regexp.MustCompile(`^ ?(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+)$`)
+	nonSlugPattern      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Slug turns a title into a lowercase, dash-separated identifier
+// ("Add login endpoint" → "add-login-endpoint"). It may return "".
+func Slug(title string) string {
+	slug := strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// TaskID derives a task ID from its title ("Add login endpoint" → "add-login-endpoint").
+func TaskID(title string) string {
+	if id := Slug(title); id != "" {
+		return id
+	}
+	return "task"
+}
+
+// ParseTasks returns the tasks of a phase plan: the frontmatter tasks list if
+// present, otherwise the top-level list items of its "## Tasks" section.
+// Tasks are returned as planned; duplicate IDs get a numeric suffix.
+func ParseTasks(content string) ([]state.Task, error) {
+	var meta PhaseMeta
+	body, err := ParseFrontmatter(content, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := meta.Tasks
+	if len(titles) == 0 {
+		titles = tasksSection(body)
+	}
+
+	var tasks []state.Task
+	seen := make(map[string]int)
+	for _, title := range titles {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+		id := TaskID(title)
+		seen[id]++
+		if seen[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, seen[id])
+		}
+		tasks = append(tasks, state.Task{ID: id, Title: title, Status: "planned"})
+	}
+	return tasks, nil
+}
+
+// tasksSection returns the list items of the "## Tasks" section of a markdown body.
+func tasksSection(body string) []string {
+	var titles []string
+	inSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if tasksHeadingPattern.MatchString(strings.TrimSpace(line)) {
+			inSection = true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if headingPattern.MatchString(line) {
+			break
+		}
+		if match := taskItemPattern.FindStringSubmatch(line); match != nil {
+			titles = append(titles, match[1])
+		}
+	}
+	return titles
+}
+
+// ReadPhaseTasks reads the tasks declared in a phase plan.
+// A missing plan file has no tasks.
+func ReadPhaseTasks(dir, phaseName string) ([]state.Task, error) {
+	tasks, err := ParseTasks(ReadFileContent(PhasePlanPath(dir, phaseName), ""))
+	if err != nil {
+		return nil, fmt.Errorf("phase %s: %w", phaseName, err)
+	}
+	return tasks, nil
+}
+
+// mergeTasks returns the plan's tasks in plan order, keeping the status of
+// tasks that were already tracked.
+func mergeTasks(existing, planned []state.Task) []state.Task {
+	status := make(map[string]string)
+	for _, task := range existing {
+		status[task.ID] = task.Status
+	}
+
+	var merged []state.Task
+	for _, task := range planned {
+		if s, ok := status[task.ID]; ok {
+			task.Status = s
+		}
+		merged = append(merged, task)
+	}
+	return merged
+}
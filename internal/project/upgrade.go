@@ -0,0 +1,216 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// GateChange is a gate whose status an upgrade changes.
+type GateChange struct {
+	Stage string
+	From  string // "" for a gate the upgrade adds
+	To    string // "" for a gate the upgrade removes
+}
+
+// Upgrade is a planned switch of a project to another preset. PlanUpgrade
+// computes it without touching the project; Apply writes it.
+type Upgrade struct {
+	From, To    string         // preset names
+	Config      *config.Config // config.yaml after the upgrade
+	State       *state.State   // state.yaml after the upgrade
+	OldWorkflow []string
+	OldStage    string
+	Gates       []GateChange
+	Dirs        []string // directories the upgrade creates
+
+	dir string
+}
+
+// PlanUpgrade computes the switch of the track in dir of the project at root
+// to another preset, in either direction (e.g. light → full or full → light).
+//
+// The workflow, reviewers and auto-advance threshold come from the new
+// preset; reviewer overrides the project set on top of its old preset win
+// for the stages that remain, and the testing settings are kept unless the
+// project has none.
+// Approved gates stay approved: the first stage of the new workflow whose
+// gate is not approved becomes the current stage. Presets that skip their
+// gates approve every stage before the last. Phases are carried over as is.
+func PlanUpgrade(root, dir, preset string) (*Upgrade, error) {
+	cfg, err := config.LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	st, err := state.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	p, err := config.FindPreset(root, preset)
+	if err != nil {
+		return nil, err
+	}
+
+	from := config.NormalizePreset(cfg.Preset)
+	if from == "" {
+		from = config.PresetFull
+	}
+	if from == p.Name && slices.Equal(cfg.GetWorkflow(), p.Workflow) {
+		return nil, fmt.Errorf("project already uses the %s preset", p.Name)
+	}
+
+	u := &Upgrade{
+		From:        from,
+		To:          p.Name,
+		OldWorkflow: slices.Clone(cfg.GetWorkflow()),
+		OldStage:    st.CurrentStage,
+		dir:         dir,
+	}
+	oldGates := make(map[string]string)
+	for _, stage := range u.OldWorkflow {
+		if gate := st.Gates[stage]; gate != nil {
+			oldGates[stage] = gate.Status
+		}
+	}
+
+	// Reviewer overrides the old preset didn't set are the project's own
+	overrides := make(map[string]string)
+	for stage, reviewer := range cfg.Reviewers.Overrides {
+		overrides[stage] = reviewer
+	}
+	if old, err := config.FindPreset(root, from); err == nil {
+		for stage, reviewer := range old.Reviewers.Overrides {
+			if overrides[stage] == reviewer {
+				delete(overrides, stage)
+			}
+		}
+	}
+
+	testing := cfg.Testing
+	cfg.ApplyPreset(p)
+	workflow := cfg.GetWorkflow()
+	for stage, reviewer := range overrides {
+		if slices.Contains(workflow, stage) {
+			cfg.Reviewers.SetReviewer(stage, reviewer)
+		}
+	}
+	if testing != nil {
+		cfg.Testing = testing
+	}
+
+	if p.Gates.Skip {
+		now := time.Now()
+		for _, stage := range workflow[:len(workflow)-1] {
+			gate := st.Gates[stage]
+			if gate == nil {
+				gate = &state.Gate{}
+				st.Gates[stage] = gate
+			}
+			if gate.Status != "approved" {
+				*gate = state.Gate{Status: "approved", ApprovedAt: &now, ApprovedBy: "auto"}
+			}
+		}
+	}
+	st.SetWorkflow(workflow)
+	for name := range st.Gates {
+		if !slices.Contains(workflow, name) {
+			delete(st.Gates, name)
+		}
+	}
+	st.MinimalMode = p.Gates.Skip
+	st.Confidence = cfg.AutoAdvance
+	u.Config, u.State = cfg, st
+
+	for _, stage := range state.Stages {
+		change := GateChange{Stage: stage, From: oldGates[stage]}
+		if gate := st.Gates[stage]; gate != nil {
+			change.To = gate.Status
+		}
+		if change.From != change.To {
+			u.Gates = append(u.Gates, change)
+		}
+	}
+
+	if cfg.HasDesignPhase() {
+		if _, err := os.Stat(DesignsPath(dir)); os.IsNotExist(err) {
+			u.Dirs = append(u.Dirs, DesignsPath(dir))
+		}
+	}
+	if cfg.HasPhasesPhase() {
+		if _, err := os.Stat(PhasesPath(dir)); os.IsNotExist(err) {
+			u.Dirs = append(u.Dirs, PhasesPath(dir))
+		}
+	}
+	return u, nil
+}
+
+// Apply creates the new directories and writes config.yaml and state.yaml.
+// Both files are replaced together: if either can't be written, neither changes.
+func (u *Upgrade) Apply() error {
+	for _, dir := range u.Dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	cfgData, err := config.Marshal(u.dir, u.Config)
+	if err != nil {
+		return err
+	}
+	stData, err := yaml.Marshal(u.State)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state.yaml: %w", err)
+	}
+
+	return writeFiles(map[string][]byte{
+		config.ConfigPath(u.dir): cfgData,
+		state.StatePath(u.dir):   stData,
+	})
+}
+
+// writeFiles replaces several files together. The new contents are written
+// to temporary files first and renamed into place; if a rename fails, the
+// files already replaced are restored.
+func writeFiles(files map[string][]byte) error {
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	originals := make(map[string][]byte)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		originals[path] = data
+	}
+
+	for i, path := range paths {
+		if err := os.WriteFile(path+".tmp", files[path], 0644); err != nil {
+			for _, written := range paths[:i+1] {
+				os.Remove(written + ".tmp")
+			}
+			return err
+		}
+	}
+
+	for i, path := range paths {
+		if err := os.Rename(path+".tmp", path); err != nil {
+			for _, replaced := range paths[:i] {
+				os.WriteFile(replaced, originals[replaced], 0644)
+			}
+			for _, pending := range paths[i:] {
+				os.Remove(pending + ".tmp")
+			}
+			return fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+	}
+	return nil
+}
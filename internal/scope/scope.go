@@ -0,0 +1,89 @@
+package scope
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// AlwaysAllowed are paths every phase may touch, regardless of its scope.
+var AlwaysAllowed = []string{".foreman/**"}
+
+// Match reports whether a slash-separated path relative to the project root
+// matches a scope pattern. Patterns support "*" and "?" within a segment and
+// "**" for any number of segments. A pattern naming a directory (with or
+// without a trailing slash) matches everything beneath it.
+func Match(pattern, file string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	file = strings.TrimPrefix(filepath.ToSlash(file), "./")
+	if pattern == "" {
+		return false
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/")) {
+		return true
+	}
+
+	// A plain directory pattern covers its contents
+	if !strings.ContainsAny(pattern, "*?[") && strings.HasPrefix(file, pattern+"/") {
+		return true
+	}
+	return false
+}
+
+// matchSegments matches pattern segments against path segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// MatchAny reports whether a file matches any of the patterns.
+func MatchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if Match(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// Outside returns the files that match neither the scope nor AlwaysAllowed.
+// An empty scope allows every file.
+func Outside(patterns, files []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	var outside []string
+	for _, f := range files {
+		if MatchAny(patterns, f) || MatchAny(AlwaysAllowed, f) {
+			continue
+		}
+		outside = append(outside, f)
+	}
+	return outside
+}
@@ -0,0 +1,55 @@
+package scope
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"internal/api/**", "internal/api/handler.go", true},
+		{"internal/api/**", "internal/api/v1/routes/user.go", true},
+		{"internal/api/**", "internal/apiary/x.go", false},
+		{"internal/api/", "internal/api/handler.go", true},
+		{"internal/api", "internal/api/handler.go", true},
+		{"cmd/*/main.go", "cmd/server/main.go", true},
+		{"cmd/*/main.go", "cmd/server/sub/main.go", false},
+		{"**/*_test.go", "internal/api/handler_test.go", true},
+		{"**/*_test.go", "main_test.go", true},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"./go.mod", "go.mod", true},
+		{"", "go.mod", false},
+	}
+
+	for _, test := range tests {
+		if got := Match(test.pattern, test.file); got != test.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", test.pattern, test.file, got, test.want)
+		}
+	}
+}
+
+func TestOutside(t *testing.T) {
+	patterns := []string{"internal/api/**", "cmd/server/**"}
+	files := []string{
+		"internal/api/handler.go",
+		"cmd/server/main.go",
+		"internal/billing/invoice.go",
+		".foreman/state.yaml",
+		"go.mod",
+	}
+
+	got := Outside(patterns, files)
+	want := []string{"internal/billing/invoice.go", "go.mod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := Outside(nil, files); got != nil {
+		t.Errorf("expected an empty scope to allow everything, got %v", got)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -33,10 +36,73 @@ type Gate struct {
 
 // Phase represents a phase within the implementation stage.
 type Phase struct {
-	Name   string `yaml:"name"`   // e.g. "1-setup", "2-backend"
+	Name        string     `yaml:"name"`                   // e.g. "1-setup", "2-backend"
+	Status      string     `yaml:"status"`                 // "planned", "in-progress", "done"
+	CompletedAt *time.Time `yaml:"completed_at,omitempty"` // when the phase was marked done
+	StartCommit string     `yaml:"start_commit,omitempty"` // git HEAD when the phase went in-progress
+	EndCommit   string     `yaml:"end_commit,omitempty"`   // git HEAD when the phase was marked done
+	Tasks       []Task     `yaml:"tasks,omitempty"`        // tasks parsed from the phase plan
+}
+
+// Task is a unit of work within a phase.
+type Task struct {
+	ID     string `yaml:"id"`     // slug of the title, e.g. "add-login-endpoint"
+	Title  string `yaml:"title"`  // as written in the phase plan
 	Status string `yaml:"status"` // "planned", "in-progress", "done"
 }
 
+// GetTask returns a task by ID or by its 1-based position.
+func (p *Phase) GetTask(ref string) *Task {
+	for i := range p.Tasks {
+		if p.Tasks[i].ID == ref {
+			return &p.Tasks[i]
+		}
+	}
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(p.Tasks) {
+		return &p.Tasks[n-1]
+	}
+	return nil
+}
+
+// TaskProgress returns the number of done tasks and the total.
+func (p *Phase) TaskProgress() (int, int) {
+	done := 0
+	for _, task := range p.Tasks {
+		if task.Status == "done" {
+			done++
+		}
+	}
+	return done, len(p.Tasks)
+}
+
+// CommitRange returns the phase's commit range as "start..end".
+// An unfinished phase ranges up to HEAD; without a start commit the range is empty.
+func (p *Phase) CommitRange() string {
+	if p.StartCommit == "" {
+		return ""
+	}
+	end := p.EndCommit
+	if end == "" {
+		end = "HEAD"
+	}
+	return p.StartCommit + ".." + end
+}
+
+// RecordCommit stores the git HEAD for a status transition: in-progress
+// starts a new range and done closes it.
+func (p *Phase) RecordCommit(status, head string) {
+	switch status {
+	case "in-progress":
+		p.StartCommit = head
+		p.EndCommit = ""
+	case "done":
+		p.EndCommit = head
+	case "planned":
+		p.StartCommit = ""
+		p.EndCommit = ""
+	}
+}
+
 // State represents the state.yaml schema.
 type State struct {
 	CurrentStage string             `yaml:"current_stage"`
@@ -47,11 +113,13 @@ type State struct {
 	Confidence   int                `yaml:"confidence,omitempty"`   // v3: auto-advance threshold (0-100)
 	Workflow     []string           `yaml:"workflow,omitempty"`     // v2.1: custom workflow stages
 	MinimalMode  bool               `yaml:"minimal_mode,omitempty"` // v2.1: no gates at all
+	GitCursor    string             `yaml:"git_cursor,omitempty"`   // last commit scanned by 'foreman sync --git'
 }
 
-// StatePath returns the path to state.yaml.
-func StatePath(root string) string {
-	return filepath.Join(root, ".foreman", "state.yaml")
+// StatePath returns the path to state.yaml in a track directory: .foreman/
+// or .foreman/tracks/<name>/.
+func StatePath(dir string) string {
+	return filepath.Join(dir, "state.yaml")
 }
 
 // IsValidStage checks if a stage name is valid.
@@ -170,14 +238,19 @@ func (s *State) IsStageInWorkflow(stage string) bool {
 	return s.GetStageIndexInWorkflow(stage) >= 0
 }
 
-// Load reads and parses state.yaml from the given root.
-func Load(root string) (*State, error) {
-	path := StatePath(root)
+// Load reads and parses state.yaml from the given track directory.
+func Load(dir string) (*State, error) {
+	path := StatePath(dir)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state.yaml: %w", err)
 	}
 	
+	return Parse(data)
+}
+
+// Parse decodes state.yaml content and fills in missing gates of the workflow's stages.
+func Parse(data []byte) (*State, error) {
 	var s State
 	if err := yaml.Unmarshal(data, &s); err != nil {
 		return nil, fmt.Errorf("failed to parse state.yaml: %w", err)
@@ -188,8 +261,8 @@ func Load(root string) (*State, error) {
 		s.Gates = make(map[string]*Gate)
 	}
 	
-	// Ensure all stages have gates
-	for _, stage := range Stages {
+	// Ensure the workflow's stages have gates
+	for _, stage := range s.GetActiveStages() {
 		if s.Gates[stage] == nil {
 			s.Gates[stage] = &Gate{Status: "blocked"}
 		}
@@ -199,8 +272,8 @@ func Load(root string) (*State, error) {
 }
 
 // Save writes the state back to state.yaml.
-func Save(root string, s *State) error {
-	path := StatePath(root)
+func Save(dir string, s *State) error {
+	path := StatePath(dir)
 	data, err := yaml.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("failed to marshal state.yaml: %w", err)
@@ -321,6 +394,43 @@ func NewWithWorkflow(workflow []string, confidence int, minimal bool) *State {
 	}
 }
 
+// SetWorkflow switches the state to a new workflow. The current stage becomes
+// the first stage whose gate is not approved, so stages added before the old
+// current stage are worked through, and the gates after it are blocked.
+func (s *State) SetWorkflow(workflow []string) {
+	if len(workflow) == 0 {
+		workflow = Stages
+	}
+	s.Workflow = append([]string(nil), workflow...)
+	s.QuickMode = len(workflow) <= 2
+	if s.Gates == nil {
+		s.Gates = make(map[string]*Gate)
+	}
+
+	current := ""
+	for _, stage := range workflow {
+		gate := s.Gates[stage]
+		if gate == nil {
+			gate = &Gate{Status: "blocked"}
+			s.Gates[stage] = gate
+		}
+		switch {
+		case gate.Status == "approved":
+		case current == "":
+			current = stage
+			if gate.Status == "blocked" {
+				gate.Status = "open"
+			}
+		case gate.Status == "open":
+			gate.Status = "blocked"
+		}
+	}
+	if current == "" {
+		current = workflow[len(workflow)-1]
+	}
+	s.CurrentStage = current
+}
+
 // CanAdvanceStage checks if we can advance from the current stage.
 func (s *State) CanAdvanceStage() bool {
 	currentGate := s.Gates[s.CurrentStage]
@@ -452,10 +562,51 @@ func (s *State) SetPhaseStatus(name, status string) error {
 		return fmt.Errorf("phase %s not found", name)
 	}
 	
+	// A phase with tasks is only done when all of them are
+	if done, total := phase.TaskProgress(); status == "done" && done < total {
+		return fmt.Errorf("phase %s has %d unfinished tasks (%d/%d done)", name, total-done, done, total)
+	}
+	
+	if status == "done" && phase.Status != "done" {
+		now := time.Now()
+		phase.CompletedAt = &now
+	} else if status != "done" {
+		phase.CompletedAt = nil
+	}
+	
 	phase.Status = status
 	return nil
 }
 
+// SetTaskStatus updates a task's status and rolls the change up to its phase:
+// starting a task starts a planned phase, finishing the last task completes
+// the phase, and reopening a task reopens a done phase.
+func (s *State) SetTaskStatus(phaseName, taskRef, status string) (*Task, error) {
+	if !IsValidPhaseStatus(status) {
+		return nil, fmt.Errorf("invalid task status: %s", status)
+	}
+	
+	phase := s.GetPhase(phaseName)
+	if phase == nil {
+		return nil, fmt.Errorf("phase %s not found", phaseName)
+	}
+	task := phase.GetTask(taskRef)
+	if task == nil {
+		return nil, fmt.Errorf("task %s not found in phase %s", taskRef, phaseName)
+	}
+	
+	task.Status = status
+	
+	done, total := phase.TaskProgress()
+	switch {
+	case done == total:
+		return task, s.SetPhaseStatus(phaseName, "done")
+	case phase.Status == "done" || (status != "planned" && phase.Status == "planned"):
+		return task, s.SetPhaseStatus(phaseName, "in-progress")
+	}
+	return task, nil
+}
+
 // AddPhase adds a new phase.
 func (s *State) AddPhase(name string) {
 	// Check if phase already exists
@@ -482,4 +633,128 @@ func (s *State) AllPhasesDone() bool {
 	}
 	
 	return true
-}
\ No newline at end of file
+}
+
+// Validate checks the state for invalid values and inconsistent stage progress.
+func (s *State) Validate() []error {
+	var errs []error
+	
+	for _, stage := range s.Workflow {
+		if !ValidStages[stage] {
+			errs = append(errs, fmt.Errorf("invalid workflow stage: %s", stage))
+		}
+	}
+	
+	if !s.IsStageInWorkflow(s.CurrentStage) {
+		errs = append(errs, fmt.Errorf("current stage %q is not in the workflow (%s)", s.CurrentStage, strings.Join(s.GetActiveStages(), ", ")))
+	} else {
+		// Every stage before the current one must have been approved
+		for _, stage := range s.GetActiveStages()[:s.GetStageIndexInWorkflow(s.CurrentStage)] {
+			if gate := s.Gates[stage]; gate == nil || gate.Status != "approved" {
+				errs = append(errs, fmt.Errorf("current stage is %s but the %s gate is not approved", s.CurrentStage, stage))
+			}
+		}
+	}
+	
+	for _, stage := range sortedGateNames(s.Gates) {
+		gate := s.Gates[stage]
+		if !IsValidGateStatus(gate.Status) {
+			errs = append(errs, fmt.Errorf("gate %s has invalid status %q", stage, gate.Status))
+		}
+		if gate.Status == "approved" && (gate.ApprovedAt == nil || gate.ApprovedBy == "") {
+			errs = append(errs, fmt.Errorf("gate %s is approved without approved_at/approved_by", stage))
+		}
+	}
+	
+	seen := make(map[string]bool)
+	for _, phase := range s.Phases {
+		if seen[phase.Name] {
+			errs = append(errs, fmt.Errorf("phase %s is listed more than once", phase.Name))
+		}
+		seen[phase.Name] = true
+		if !IsValidPhaseStatus(phase.Status) {
+			errs = append(errs, fmt.Errorf("phase %s has invalid status %q", phase.Name, phase.Status))
+		}
+		for _, task := range phase.Tasks {
+			if !IsValidPhaseStatus(task.Status) {
+				errs = append(errs, fmt.Errorf("task %s/%s has invalid status %q", phase.Name, task.ID, task.Status))
+			}
+		}
+		if done, total := phase.TaskProgress(); phase.Status == "done" && done < total {
+			errs = append(errs, fmt.Errorf("phase %s is done but %d of its tasks are not", phase.Name, total-done))
+		}
+	}
+	
+	return errs
+}
+
+// ValidateTransition checks the change from prev (e.g. the committed state)
+// to s. Recorded history may only move forward: approvals and phase
+// completions in prev may be replaced by later ones but not edited in place
+// or backdated, and new approvals may not predate the approvals in prev.
+func (s *State) ValidateTransition(prev *State) []error {
+	var errs []error
+	
+	var latest time.Time
+	for _, gate := range prev.Gates {
+		if gate.Status == "approved" && gate.ApprovedAt != nil && gate.ApprovedAt.After(latest) {
+			latest = *gate.ApprovedAt
+		}
+	}
+	
+	for _, stage := range sortedGateNames(s.Gates) {
+		gate := s.Gates[stage]
+		if gate.Status != "approved" || gate.ApprovedAt == nil {
+			continue
+		}
+		old := prev.Gates[stage]
+		if old != nil && old.Status == "approved" && old.ApprovedAt != nil {
+			if rewritten(*old.ApprovedAt, *gate.ApprovedAt, old.ApprovedBy != gate.ApprovedBy) {
+				errs = append(errs, fmt.Errorf("the approval of gate %s was edited (approved %s by %s at HEAD)", stage, old.ApprovedAt.Format(time.RFC3339), old.ApprovedBy))
+			}
+			continue
+		}
+		if gate.ApprovedAt.Before(latest) {
+			errs = append(errs, fmt.Errorf("gate %s is approved at %s, before approvals already committed", stage, gate.ApprovedAt.Format(time.RFC3339)))
+		}
+	}
+	
+	for _, phase := range s.Phases {
+		old := prev.GetPhase(phase.Name)
+		if old == nil || old.Status != "done" || phase.Status != "done" || old.CompletedAt == nil || phase.CompletedAt == nil {
+			continue
+		}
+		if rewritten(*old.CompletedAt, *phase.CompletedAt, old.StartCommit != phase.StartCommit || old.EndCommit != phase.EndCommit) {
+			errs = append(errs, fmt.Errorf("the completion record of phase %s was edited", phase.Name))
+		}
+	}
+	
+	return errs
+}
+
+// rewritten reports whether a record made at prev was edited in place
+// (same time, other fields changed) or replaced by an earlier one.
+func rewritten(prev, next time.Time, changed bool) bool {
+	if next.Equal(prev) {
+		return changed
+	}
+	return next.Before(prev)
+}
+
+// sortedGateNames returns gate names in stage order, then any unknown names.
+func sortedGateNames(gates map[string]*Gate) []string {
+	var names []string
+	for _, stage := range Stages {
+		if gates[stage] != nil {
+			names = append(names, stage)
+		}
+	}
+	var extra []string
+	for name := range gates {
+		if !ValidStages[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestStageValidation(t *testing.T) {
@@ -232,12 +235,12 @@ func TestLoadSave(t *testing.T) {
 	state.AddPhase("1-setup")
 	state.SetPhaseStatus("1-setup", "in-progress")
 	
-	if err := Save(tempDir, state); err != nil {
+	if err := Save(foremanDir, state); err != nil {
 		t.Fatal(err)
 	}
 	
 	// Load state back
-	loaded, err := Load(tempDir)
+	loaded, err := Load(foremanDir)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -254,4 +257,202 @@ func TestLoadSave(t *testing.T) {
 	if loaded.GetPhase("1-setup").Status != "in-progress" {
 		t.Errorf("expected phase status 'in-progress', got '%s'", loaded.GetPhase("1-setup").Status)
 	}
-}
\ No newline at end of file
+}
+
+func TestPhaseCompletedAt(t *testing.T) {
+	state := NewDefault()
+	state.AddPhase("1-setup")
+
+	if err := state.SetPhaseStatus("1-setup", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if state.GetPhase("1-setup").CompletedAt == nil {
+		t.Error("expected completion time to be set")
+	}
+
+	if err := state.SetPhaseStatus("1-setup", "in-progress"); err != nil {
+		t.Fatal(err)
+	}
+	if state.GetPhase("1-setup").CompletedAt != nil {
+		t.Error("expected completion time to be cleared when reopened")
+	}
+}
+
+func TestPhaseCommitRange(t *testing.T) {
+	state := NewDefault()
+	state.AddPhase("1-setup")
+	phase := state.GetPhase("1-setup")
+
+	if phase.CommitRange() != "" {
+		t.Errorf("expected empty range before the phase starts, got %q", phase.CommitRange())
+	}
+
+	phase.RecordCommit("in-progress", "aaa111")
+	if got := phase.CommitRange(); got != "aaa111..HEAD" {
+		t.Errorf("expected open range aaa111..HEAD, got %q", got)
+	}
+
+	phase.RecordCommit("done", "bbb222")
+	if got := phase.CommitRange(); got != "aaa111..bbb222" {
+		t.Errorf("expected closed range aaa111..bbb222, got %q", got)
+	}
+
+	// Restarting the phase opens a new range
+	phase.RecordCommit("in-progress", "ccc333")
+	if phase.EndCommit != "" || phase.StartCommit != "ccc333" {
+		t.Errorf("expected new range from ccc333, got %s..%s", phase.StartCommit, phase.EndCommit)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	state := NewDefault()
+	state.AddPhase("1-setup")
+	if errs := state.Validate(); len(errs) != 0 {
+		t.Errorf("expected default state to be valid, got %v", errs)
+	}
+	if errs := NewMinimalMode("fix bug").Validate(); len(errs) != 0 {
+		t.Errorf("expected minimal mode state to be valid, got %v", errs)
+	}
+
+	// Jumping ahead without approving earlier gates is invalid
+	state.CurrentStage = "implementation"
+	state.Gates["requirements"].Status = "approved"
+	state.Phases[0].Status = "finished"
+	errs := state.Validate()
+	if len(errs) != 4 {
+		t.Errorf("expected 4 problems (unapproved design/phases, approval without reviewer, bad phase status), got %v", errs)
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	prev := NewDefault()
+	if err := prev.ApproveGate("requirements", "human"); err != nil {
+		t.Fatal(err)
+	}
+	prev.AddPhase("1-setup")
+	if err := prev.SetPhaseStatus("1-setup", "done"); err != nil {
+		t.Fatal(err)
+	}
+	prev.Phases[0].EndCommit = "abc123"
+
+	next, err := Parse(mustMarshal(t, prev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := next.ApproveGate("design", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if errs := next.ValidateTransition(prev); len(errs) != 0 {
+		t.Errorf("expected a new approval to be a valid transition, got %v", errs)
+	}
+
+	// Backdating a new approval, editing a committed one or a phase's completion is not
+	backdated := prev.Gates["requirements"].ApprovedAt.Add(-time.Hour)
+	next.Gates["design"].ApprovedAt = &backdated
+	next.Gates["requirements"].ApprovedBy = "auto"
+	next.Phases[0].EndCommit = "def456"
+	if errs := next.ValidateTransition(prev); len(errs) != 3 {
+		t.Errorf("expected 3 problems (edited approval, backdated approval, edited completion), got %v", errs)
+	}
+}
+
+func mustMarshal(t *testing.T, s *State) []byte {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSetWorkflow(t *testing.T) {
+	// Quick project past requirements switches to the full workflow
+	state := NewWithWorkflow(QuickStages, 0, false)
+	if err := state.ApproveGate("requirements", "auto"); err != nil {
+		t.Fatal(err)
+	}
+
+	state.SetWorkflow(Stages)
+	if state.CurrentStage != "design" || state.Gates["design"].Status != "open" {
+		t.Errorf("expected to resume at the added design stage, got %s (%s)", state.CurrentStage, state.Gates["design"].Status)
+	}
+	if state.Gates["implementation"].Status != "blocked" || state.QuickMode {
+		t.Errorf("expected implementation blocked in full mode, got %s (quick %v)", state.Gates["implementation"].Status, state.QuickMode)
+	}
+	if errs := state.Validate(); len(errs) != 0 {
+		t.Errorf("expected consistent state, got %v", errs)
+	}
+
+	// Dropping the current stage moves on to the next open one
+	state.SetWorkflow([]string{"requirements", "implementation"})
+	if state.CurrentStage != "implementation" || state.Gates["implementation"].Status != "open" {
+		t.Errorf("expected implementation to be current, got %s", state.CurrentStage)
+	}
+	if errs := state.Validate(); len(errs) != 0 {
+		t.Errorf("expected consistent state, got %v", errs)
+	}
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte("current_stage: design\ngates:\n  requirements:\n    status: approved\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.CurrentStage != "design" || s.Gates["implementation"] == nil {
+		t.Errorf("expected parsed state with all gates, got %+v", s)
+	}
+
+	if _, err := Parse([]byte("current_stage: [")); err == nil {
+		t.Error("expected error for malformed yaml")
+	}
+}
+
+func TestTaskRollup(t *testing.T) {
+	state := NewDefault()
+	state.AddPhase("1-setup")
+	phase := state.GetPhase("1-setup")
+	phase.Tasks = []Task{
+		{ID: "init-module", Title: "Init module", Status: "planned"},
+		{ID: "add-ci", Title: "Add CI", Status: "planned"},
+	}
+
+	if err := state.SetPhaseStatus("1-setup", "done"); err == nil {
+		t.Error("expected phase with unfinished tasks to refuse done")
+	}
+
+	// Starting a task starts the phase
+	if _, err := state.SetTaskStatus("1-setup", "init-module", "in-progress"); err != nil {
+		t.Fatal(err)
+	}
+	if phase.Status != "in-progress" {
+		t.Errorf("expected phase in-progress, got %s", phase.Status)
+	}
+
+	// Tasks can be referenced by position; the last one done completes the phase
+	if _, err := state.SetTaskStatus("1-setup", "1", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if phase.Status != "in-progress" {
+		t.Errorf("expected phase to stay in-progress with one task left, got %s", phase.Status)
+	}
+	if _, err := state.SetTaskStatus("1-setup", "add-ci", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if phase.Status != "done" || phase.CompletedAt == nil {
+		t.Errorf("expected phase done after all tasks, got %s", phase.Status)
+	}
+
+	// Reopening a task reopens the phase
+	if _, err := state.SetTaskStatus("1-setup", "add-ci", "planned"); err != nil {
+		t.Fatal(err)
+	}
+	if phase.Status != "in-progress" {
+		t.Errorf("expected phase reopened, got %s", phase.Status)
+	}
+
+	if _, err := state.SetTaskStatus("1-setup", "missing", "done"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+	if _, err := state.SetTaskStatus("1-setup", "add-ci", "finished"); err == nil {
+		t.Error("expected error for invalid task status")
+	}
+}
@@ -0,0 +1,299 @@
+// Package wizard implements the interactive questionnaire behind
+// 'foreman init --interactive'. It reads one answer per line, so it can be
+// driven from a terminal or scripted through stdin.
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+)
+
+// Option is one choice of a multiple-choice question.
+type Option struct {
+	Value       string
+	Description string
+}
+
+// TestingOptions explains the testing styles.
+var TestingOptions = []Option{
+	{config.TestingStyleNone, "No testing requirements"},
+	{config.TestingStyleTDD, "Test-driven development: briefs ask for tests first"},
+	{config.TestingStyleCoverage, "Coverage target: briefs ask for a minimum coverage"},
+}
+
+// ReviewerOptions explains the gate reviewers.
+var ReviewerOptions = []Option{
+	{"auto", "Gate passes as soon as validation succeeds"},
+	{"human", "Gate waits for 'foreman gate <stage> --approve'"},
+}
+
+// Wizard asks questions on out and reads the answers from in.
+// An empty answer, or the end of input, takes the default.
+type Wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	eof bool
+}
+
+// New creates a wizard reading answers from in and writing prompts to out.
+func New(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out}
+}
+
+// readLine reads the next answer. Once the input is exhausted every answer is empty.
+func (w *Wizard) readLine() (string, error) {
+	if w.eof {
+		fmt.Fprintln(w.out)
+		return "", nil
+	}
+	line, err := w.in.ReadString('\n')
+	if err == io.EOF {
+		w.eof = true
+		if line == "" {
+			fmt.Fprintln(w.out)
+		}
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// retry reports an invalid answer. Without more input to retry with, it's an error.
+func (w *Wizard) retry(problem string) error {
+	if w.eof {
+		return fmt.Errorf("%s", problem)
+	}
+	fmt.Fprintf(w.out, "  %s\n", problem)
+	return nil
+}
+
+// Ask asks a free-form question.
+func (w *Wizard) Ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	answer, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// Choose asks a multiple-choice question. Answers may be an option's value or its number.
+func (w *Wizard) Choose(question string, options []Option, def string) (string, error) {
+	fmt.Fprintf(w.out, "%s\n", question)
+	for i, option := range options {
+		fmt.Fprintf(w.out, "  %d) %-9s %s\n", i+1, option.Value, option.Description)
+	}
+
+	for {
+		answer, err := w.Ask("Choice", def)
+		if err != nil {
+			return "", err
+		}
+		answer = strings.ToLower(answer)
+		for i, option := range options {
+			if answer == option.Value || answer == strconv.Itoa(i+1) {
+				return option.Value, nil
+			}
+		}
+		if err := w.retry(fmt.Sprintf("invalid choice %q", answer)); err != nil {
+			return "", err
+		}
+	}
+}
+
+// Confirm asks a yes/no question.
+func (w *Wizard) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, hint)
+		answer, err := w.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		if err := w.retry(fmt.Sprintf("please answer y or n, not %q", answer)); err != nil {
+			return false, err
+		}
+	}
+}
+
+// AskNumber asks for an integer between min and max.
+func (w *Wizard) AskNumber(question string, def, min, max int) (int, error) {
+	for {
+		answer, err := w.Ask(question, strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= min && n <= max {
+			return n, nil
+		}
+		if err := w.retry(fmt.Sprintf("expected a number from %d to %d, got %q", min, max, answer)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// InitOptions walks through the project settings, starting from defaults
+// (e.g. the values of command-line flags), and returns the chosen options.
+// root is the project a track is added to, whose presets are offered too; it
+// is "" for a new project.
+func (w *Wizard) InitOptions(root string, defaults project.InitOptions) (project.InitOptions, error) {
+	opts := defaults
+	var err error
+
+	if opts.Name, err = w.Ask("Project name", defaults.Name); err != nil {
+		return opts, err
+	}
+	if opts.Description, err = w.Ask("Description", defaults.Description); err != nil {
+		return opts, err
+	}
+	stack, err := w.Ask("Tech stack (comma-separated)", strings.Join(defaults.TechStack, ", "))
+	if err != nil {
+		return opts, err
+	}
+	opts.TechStack = splitList(stack)
+
+	// Built-in and user-defined presets
+	presets, err := config.LoadPresets(root)
+	if err != nil {
+		return opts, err
+	}
+	var presetOptions []Option
+	for _, p := range presets {
+		presetOptions = append(presetOptions, Option{p.Name, p.Description})
+	}
+
+	fmt.Fprintln(w.out)
+	preset := config.NormalizePreset(defaults.Preset)
+	if preset == "" {
+		preset = config.PresetFull
+	}
+	if opts.Preset, err = w.Choose("Workflow preset:", presetOptions, preset); err != nil {
+		return opts, err
+	}
+	presetDef, err := config.FindPreset(root, opts.Preset)
+	if err != nil {
+		return opts, err
+	}
+
+	fmt.Fprintln(w.out)
+	testing, err := w.askTesting(defaults, presetDef)
+	if err != nil {
+		return opts, err
+	}
+	opts.Testing = testing
+	opts.TDD = testing != nil && testing.Style == config.TestingStyleTDD
+
+	// Presets that skip their gates have nothing to review
+	cfg := config.NewFromPreset(opts.Name, presetDef)
+	opts.Reviewers = nil
+	if !presetDef.Gates.Skip {
+		fmt.Fprintln(w.out)
+		fmt.Fprintln(w.out, "Gate reviewers:")
+		for _, option := range ReviewerOptions {
+			fmt.Fprintf(w.out, "  %-9s %s\n", option.Value, option.Description)
+		}
+		for _, stage := range cfg.GetWorkflow() {
+			def := cfg.Reviewers.GetReviewer(stage)
+			if r, ok := defaults.Reviewers[stage]; ok {
+				def = r
+			}
+			reviewer, err := w.askReviewer(stage, def)
+			if err != nil {
+				return opts, err
+			}
+			if reviewer != cfg.Reviewers.GetReviewer(stage) {
+				if opts.Reviewers == nil {
+					opts.Reviewers = make(map[string]string)
+				}
+				opts.Reviewers[stage] = reviewer
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// askTesting asks for the testing style and its settings. It returns nil for no testing.
+func (w *Wizard) askTesting(defaults project.InitOptions, preset *config.Preset) (*config.Testing, error) {
+	def := config.TestingStyleNone
+	if defaults.Testing != nil && defaults.Testing.Style != "" {
+		def = defaults.Testing.Style
+	} else if defaults.TDD {
+		def = config.TestingStyleTDD
+	} else if preset.Testing != nil && preset.Testing.Style != "" {
+		def = preset.Testing.Style
+	}
+
+	style, err := w.Choose("Testing style:", TestingOptions, def)
+	if err != nil || style == config.TestingStyleNone {
+		return nil, err
+	}
+
+	testing := &config.Testing{Style: style}
+	if style == config.TestingStyleCoverage {
+		if testing.MinCover, err = w.AskNumber("Minimum coverage %", 80, 1, 100); err != nil {
+			return nil, err
+		}
+	}
+	if testing.Framework, err = w.Ask("Test framework (e.g. go test, vitest)", ""); err != nil {
+		return nil, err
+	}
+	if testing.Required, err = w.Confirm("Block phase completion without tests?", false); err != nil {
+		return nil, err
+	}
+	return testing, nil
+}
+
+// askReviewer asks who reviews a stage's gate.
+func (w *Wizard) askReviewer(stage, def string) (string, error) {
+	for {
+		answer, err := w.Ask(fmt.Sprintf("Reviewer for the %s gate (auto/human)", stage), def)
+		if err != nil {
+			return "", err
+		}
+		answer = strings.ToLower(answer)
+		if answer == "auto" || answer == "human" {
+			return answer, nil
+		}
+		if err := w.retry(fmt.Sprintf("invalid reviewer %q", answer)); err != nil {
+			return "", err
+		}
+	}
+}
+
+// splitList splits a comma-separated answer into trimmed, non-empty items.
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,165 @@
+package wizard
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+)
+
+// TestMain keeps the user's global config and FOREMAN_* variables out of the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "foreman-user-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, config.EnvPrefix) {
+			os.Unsetenv(key)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestInitOptions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	answers := strings.Join([]string{
+		"my-app",      // name
+		"A demo app",  // description
+		"Go, cobra, ", // tech stack
+		"3",           // preset by number
+		"coverage",    // testing style
+		"150",         // invalid coverage...
+		"90",          // ...retried
+		"go test",     // framework
+		"y",           // required
+		"",            // requirements reviewer: keep human
+		"auto",        // design reviewer
+		"",            // phases reviewer: keep auto
+		"human",       // implementation reviewer
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	opts, err := New(strings.NewReader(answers), &out).InitOptions("", project.InitOptions{Name: "dir-name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+
+	if opts.Name != "my-app" || opts.Description != "A demo app" || opts.Preset != config.PresetFull {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if strings.Join(opts.TechStack, "|") != "Go|cobra" {
+		t.Errorf("unexpected tech stack: %v", opts.TechStack)
+	}
+	if opts.Testing == nil || opts.Testing.Style != config.TestingStyleCoverage || opts.Testing.MinCover != 90 || opts.Testing.Framework != "go test" || !opts.Testing.Required {
+		t.Errorf("unexpected testing: %+v", opts.Testing)
+	}
+	if len(opts.Reviewers) != 2 || opts.Reviewers["design"] != "auto" || opts.Reviewers["implementation"] != "human" {
+		t.Errorf("expected only changed reviewers, got %v", opts.Reviewers)
+	}
+	if !strings.Contains(out.String(), "expected a number from 1 to 100") {
+		t.Errorf("expected invalid answer to be reported:\n%s", out.String())
+	}
+}
+
+func TestInitOptionsDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// End of input takes every default, starting from the flag values
+	var out bytes.Buffer
+	opts, err := New(strings.NewReader(""), &out).InitOptions("", project.InitOptions{Name: "tool", Preset: "nightly", TDD: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Name != "tool" || opts.Preset != config.PresetMinimal || !opts.TDD {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if opts.Reviewers != nil {
+		t.Errorf("expected no reviewer questions for minimal, got %v", opts.Reviewers)
+	}
+	if strings.Contains(out.String(), "Reviewer for") {
+		t.Errorf("minimal projects have no gates to review:\n%s", out.String())
+	}
+}
+
+func TestInvalidAnswerAtEndOfInput(t *testing.T) {
+	w := New(strings.NewReader("huge"), &bytes.Buffer{})
+	if _, err := w.Choose("Reviewer:", ReviewerOptions, "auto"); err == nil {
+		t.Error("expected an invalid final answer to fail instead of looping")
+	}
+}
+
+func TestInitOptionsCreateProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	answers := "scripted\n\nGo\nlight\ntdd\n\n\nhuman\n\n"
+
+	opts, err := New(strings.NewReader(answers), &bytes.Buffer{}).InitOptions("", project.InitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := project.InitWithOptions(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(project.ForemanPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "scripted" || cfg.Preset != config.PresetLight || !cfg.IsTDDEnabled() {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Reviewers.GetReviewer("requirements") != "human" || cfg.Reviewers.GetReviewer("implementation") != "auto" {
+		t.Errorf("unexpected reviewers: %+v", cfg.Reviewers)
+	}
+}
+
+func TestInitOptionsCustomPreset(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	dir := filepath.Join(xdg, "foreman", "presets")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	preset := "description: Team default\nworkflow: [requirements, design, implementation]\ntesting:\n  style: tdd\n"
+	if err := os.WriteFile(filepath.Join(dir, "team.yaml"), []byte(preset), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts, err := New(strings.NewReader("app\n\n\nteam\n"), &out).InitOptions("", project.InitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Preset != "team" || !opts.TDD {
+		t.Errorf("expected the team preset and its testing style as default, got %+v", opts)
+	}
+	if !strings.Contains(out.String(), "4) team") || !strings.Contains(out.String(), "Reviewer for the design gate") {
+		t.Errorf("expected the custom preset to be offered and its stages asked about:\n%s", out.String())
+	}
+	// A track also gets the presets of its project
+	root := t.TempDir()
+	projectDir := filepath.Join(root, ".foreman", "presets")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "billing.yaml"), []byte("workflow: [requirements, implementation]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts, err = New(strings.NewReader("app\n\n\nbilling\n"), &bytes.Buffer{}).InitOptions(root, project.InitOptions{Track: "billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Preset != "billing" {
+		t.Errorf("expected the project preset to be offered, got %+v", opts)
+	}
+}
@@ -0,0 +1,263 @@
+// Package workspace finds the foreman projects of a monorepo and summarizes
+// their progress.
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the optional file listing the projects of a workspace.
+const FileName = "foreman-workspace.yaml"
+
+// File is the content of foreman-workspace.yaml.
+type File struct {
+	// Project directories relative to the file; glob patterns such as
+	// services/* match every directory with a .foreman/ in it.
+	Projects []string `yaml:"projects"`
+}
+
+// Workspace is a set of foreman projects under a common root.
+type Workspace struct {
+	Root     string   // directory of foreman-workspace.yaml, or the searched directory
+	File     string   // path of foreman-workspace.yaml, "" if the projects were discovered
+	Projects []string // project roots, sorted
+	Missing  []string // entries of the workspace file without a .foreman/
+}
+
+// skipDirs are not searched for projects.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// FindFile walks up from dir looking for foreman-workspace.yaml and returns
+// its path, or "" if there is none.
+func FindFile(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(abs, FileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", nil
+		}
+		abs = parent
+	}
+}
+
+// Open returns the workspace containing dir. The projects are read from the
+// nearest foreman-workspace.yaml at or above dir; without one, every
+// directory under dir that has a .foreman/ is a project.
+func Open(dir string) (*Workspace, error) {
+	path, err := FindFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		return Load(path)
+	}
+	return Discover(dir)
+}
+
+// Within returns the workspace restricted to the projects and missing
+// entries at or under dir.
+func (ws *Workspace) Within(dir string) (*Workspace, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	within := &Workspace{Root: ws.Root, File: ws.File}
+	for _, p := range ws.Projects {
+		if isUnder(p, abs) {
+			within.Projects = append(within.Projects, p)
+		}
+	}
+	for _, entry := range ws.Missing {
+		if isUnder(filepath.Join(ws.Root, filepath.FromSlash(entry)), abs) {
+			within.Missing = append(within.Missing, entry)
+		}
+	}
+	return within, nil
+}
+
+// isUnder reports whether path is dir or inside it.
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	return rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// Load reads the workspace described by a foreman-workspace.yaml file.
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	ws := &Workspace{Root: filepath.Dir(path), File: path}
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			ws.Projects = append(ws.Projects, dir)
+		}
+	}
+	for _, entry := range file.Projects {
+		pattern := filepath.Join(ws.Root, filepath.FromSlash(entry))
+		if !strings.ContainsAny(entry, "*?[") {
+			if isProject(pattern) {
+				add(pattern)
+			} else {
+				ws.Missing = append(ws.Missing, entry)
+			}
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project pattern %q in %s: %w", entry, FileName, err)
+		}
+		for _, match := range matches {
+			if isProject(match) {
+				add(match)
+			}
+		}
+	}
+	sort.Strings(ws.Projects)
+	return ws, nil
+}
+
+// Discover finds every project under dir, including dir itself. Hidden
+// directories, node_modules and vendor are not searched.
+func Discover(dir string) (*Workspace, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	ws := &Workspace{Root: root}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return fs.SkipDir // unreadable directories can't hold projects we can read
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+			return fs.SkipDir
+		}
+		if isProject(path) {
+			ws.Projects = append(ws.Projects, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ws.Projects)
+	return ws, nil
+}
+
+// isProject reports whether dir has a .foreman/ directory.
+func isProject(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, project.ForemanDir))
+	return err == nil && info.IsDir()
+}
+
+// ProjectStatus summarizes one project of the workspace.
+type ProjectStatus struct {
+	Name           string   `json:"name"`
+	Path           string   `json:"path"` // relative to the workspace root
+	Preset         string   `json:"preset,omitempty"`
+	Stage          string   `json:"stage,omitempty"`
+	StageNumber    int      `json:"stage_number,omitempty"` // 1-based position in the workflow
+	Stages         int      `json:"stages,omitempty"`
+	PendingReviews []string `json:"pending_reviews"` // stages whose gate awaits review
+	PhasesDone     int      `json:"phases_done"`
+	Phases         int      `json:"phases"`
+	Error          string   `json:"error,omitempty"` // why the project couldn't be read
+}
+
+// Pending reports whether the project has gates awaiting review.
+func (s ProjectStatus) Pending() bool {
+	return len(s.PendingReviews) > 0
+}
+
+// Status summarizes every project of the workspace. Projects whose config or
+// state can't be read are included with their error.
+func (ws *Workspace) Status() []ProjectStatus {
+	statuses := make([]ProjectStatus, 0, len(ws.Projects))
+	for _, root := range ws.Projects {
+		statuses = append(statuses, ws.projectStatus(root))
+	}
+	return statuses
+}
+
+func (ws *Workspace) projectStatus(root string) ProjectStatus {
+	rel, err := filepath.Rel(ws.Root, root)
+	if err != nil {
+		rel = root
+	}
+	s := ProjectStatus{
+		Name:           filepath.Base(root),
+		Path:           filepath.ToSlash(rel),
+		PendingReviews: []string{},
+	}
+
+	dir := project.ForemanPath(root)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	if cfg.Name != "" {
+		s.Name = cfg.Name
+	}
+	s.Preset = cfg.Preset
+
+	st, err := state.Load(dir)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	stages := st.GetActiveStages()
+	s.Stage = st.CurrentStage
+	s.StageNumber = st.GetStageIndexInWorkflow(st.CurrentStage) + 1
+	s.Stages = len(stages)
+	for _, stage := range stages {
+		if gate := st.Gates[stage]; gate != nil && gate.Status == "pending-review" {
+			s.PendingReviews = append(s.PendingReviews, stage)
+		}
+	}
+	for _, phase := range st.Phases {
+		if phase.Status == "done" {
+			s.PhasesDone++
+		}
+	}
+	s.Phases = len(st.Phases)
+	return s
+}
@@ -0,0 +1,154 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thinkshake/foreman/internal/config"
+	"github.com/thinkshake/foreman/internal/project"
+	"github.com/thinkshake/foreman/internal/state"
+)
+
+// TestMain keeps the user's global config and FOREMAN_* variables out of the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "foreman-user-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, config.EnvPrefix) {
+			os.Unsetenv(key)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func initProject(t *testing.T, dir, preset string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	root, err := project.InitWithOptions(dir, project.InitOptions{Name: filepath.Base(dir), Preset: preset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func names(ws *Workspace) string {
+	var rels []string
+	for _, root := range ws.Projects {
+		rel, _ := filepath.Rel(ws.Root, root)
+		rels = append(rels, filepath.ToSlash(rel))
+	}
+	return strings.Join(rels, ",")
+}
+
+func TestDiscoverAndLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	initProject(t, filepath.Join(dir, "services", "api"), config.PresetLight)
+	initProject(t, filepath.Join(dir, "services", "web"), config.PresetFull)
+	initProject(t, filepath.Join(dir, "tools"), config.PresetMinimal)
+	initProject(t, filepath.Join(dir, "node_modules", "dep"), config.PresetMinimal)
+	initProject(t, filepath.Join(dir, ".cache", "copy"), config.PresetMinimal)
+
+	ws, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.File != "" || names(ws) != "services/api,services/web,tools" {
+		t.Errorf("expected discovered projects, got %q (file %q)", names(ws), ws.File)
+	}
+
+	// A workspace file selects the projects, also from a subdirectory
+	content := "projects:\n  - services/*\n  - gone\n"
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ws, err = Open(filepath.Join(dir, "services", "api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.File == "" || names(ws) != "services/api,services/web" {
+		t.Errorf("expected projects from %s, got %q", FileName, names(ws))
+	}
+	if strings.Join(ws.Missing, ",") != "gone" {
+		t.Errorf("expected missing entry to be reported, got %v", ws.Missing)
+	}
+
+	// Within narrows the workspace to a directory
+	services, err := ws.Within(filepath.Join(dir, "services"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services.File != ws.File || names(services) != "services/api,services/web" || len(services.Missing) != 0 {
+		t.Errorf("expected the projects under services/, got %q (missing %v)", names(services), services.Missing)
+	}
+	api, err := ws.Within(filepath.Join(dir, "services", "api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names(api) != "services/api" {
+		t.Errorf("expected only services/api, got %q", names(api))
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	initProject(t, filepath.Join(dir, "api"), config.PresetLight)
+	web := initProject(t, filepath.Join(dir, "web"), config.PresetFull)
+	broken := initProject(t, filepath.Join(dir, "broken"), config.PresetLight)
+
+	st, err := state.Load(project.ForemanPath(web))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.ApproveGate("requirements", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetGateStatus("design", "pending-review"); err != nil {
+		t.Fatal(err)
+	}
+	st.AddPhase("1-setup")
+	st.AddPhase("2-api")
+	if err := st.SetPhaseStatus("1-setup", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(project.ForemanPath(web), st); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.StatePath(project.ForemanPath(broken)), []byte("gates: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := ws.Status()
+	if len(statuses) != 3 {
+		t.Fatalf("expected 3 projects, got %+v", statuses)
+	}
+	api, bad, w := statuses[0], statuses[1], statuses[2]
+
+	if api.Name != "api" || api.Stage != "requirements" || api.StageNumber != 1 || api.Stages != 2 || api.Pending() {
+		t.Errorf("unexpected status for api: %+v", api)
+	}
+	if bad.Path != "broken" || bad.Error == "" {
+		t.Errorf("expected unreadable state to be reported, got %+v", bad)
+	}
+	if w.Stage != "design" || w.StageNumber != 2 || w.Stages != 4 {
+		t.Errorf("expected web at design (2/4), got %+v", w)
+	}
+	if strings.Join(w.PendingReviews, ",") != "design" || w.PhasesDone != 1 || w.Phases != 2 {
+		t.Errorf("expected pending design review and 1/2 phases, got %+v", w)
+	}
+}